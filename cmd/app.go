@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/config"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/projection"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/psql"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/sqlite"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/sqliteclient"
+)
+
+// app wires the catalog for the CLI commands. Event handlers of the
+// projections are registered, side-effecting handlers such as alerts are not.
+// With the SQLite driver there are no projections and queries is nil, so
+// the commands that need them are rejected before the app is built.
+type app struct {
+	cfg      *config.Config
+	logger   *slog.Logger
+	db       *sqlx.DB
+	usecases *application.Usecases
+	queries  *application.Queries
+
+	caseProjector       *projection.CaseProjector
+	turnaroundProjector *projection.TurnaroundProjector
+	worklistProjector   *projection.WorklistProjector
+}
+
+func newApp(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*app, error) {
+	env := domain.SystemEnv()
+	service := domain.NewService(env)
+
+	if cfg.Storage.Driver == config.StorageDriverSQLite {
+		db, err := sqliteclient.New(ctx, sqliteclient.Config{Path: cfg.SQLite.Path})
+		if err != nil {
+			return nil, fmt.Errorf("connect to sqlite: %w", err)
+		}
+		return &app{
+			cfg:      cfg,
+			logger:   logger,
+			db:       db,
+			usecases: application.NewUsecases(sqlite.NewStorage(db, logger), service, env, logger),
+		}, nil
+	}
+
+	db, err := psqlclient.New(ctx, psqlConfig(cfg.PSQL))
+	if err != nil {
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+
+	var replica *sqlx.DB
+	if cfg.PSQLReplica.Host != "" {
+		replica, err = psqlclient.New(ctx, psqlConfig(cfg.PSQLReplica.PSQL))
+		if err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("connect to postgres replica: %w", err)
+		}
+	}
+	router := psqlclient.NewRouter(db, replica, cfg.PSQLReplica.ReadYourWrites)
+
+	a := &app{
+		cfg:                 cfg,
+		logger:              logger,
+		db:                  db,
+		usecases:            application.NewUsecases(psql.NewStorage(router, logger), service, env, logger),
+		caseProjector:       projection.NewCaseProjectior(router, service),
+		turnaroundProjector: projection.NewTurnaroundProjector(router),
+		worklistProjector:   projection.NewWorklistProjector(router),
+	}
+	a.queries = application.NewQueries(a.caseProjector, a.turnaroundProjector, a.worklistProjector)
+	a.registerProjections()
+
+	return a, nil
+}
+
+func psqlConfig(cfg config.PSQL) psqlclient.Config {
+	return psqlclient.Config{
+		Username:         cfg.User,
+		Password:         cfg.Password,
+		Host:             cfg.Host,
+		Port:             cfg.Port,
+		Database:         cfg.DB,
+		SSLMode:          cfg.SSLMode,
+		SSLRootCert:      cfg.SSLRootCert,
+		ApplicationName:  cfg.ApplicationName,
+		MaxOpenConns:     cfg.MaxOpenConns,
+		MaxIdleConns:     cfg.MaxIdleConns,
+		ConnMaxLifetime:  cfg.ConnMaxLifetime,
+		ConnMaxIdleTime:  cfg.ConnMaxIdleTime,
+		StatementTimeout: cfg.StatementTimeout,
+		ConnectTimeout:   cfg.ConnectTimeout,
+		ConnectRetries:   cfg.ConnectRetries,
+		ConnectBackoff:   cfg.ConnectBackoff,
+	}
+}
+
+func (a *app) registerProjections() {
+	u := a.usecases
+	caseProjector, turnaroundProjector, worklistProjector := a.caseProjector, a.turnaroundProjector, a.worklistProjector
+
+	u.RegisterEventHandler(domain.EventTypeCaseCreated, caseProjector.HandleCaseCreated)
+	u.RegisterEventHandler(domain.EventTypeCaseSignedOut, caseProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseHeld, caseProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseHoldReleased, caseProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseCancelled, caseProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseReopened, caseProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeSlideCreated, caseProjector.HandleSlideCreated)
+	u.RegisterEventHandler(domain.EventTypeSlideStarted, caseProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideFinished, caseProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideFailed, caseProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideTimedOut, caseProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideReleased, caseProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideCreated, turnaroundProjector.HandleSlideCreated)
+	u.RegisterEventHandler(domain.EventTypeSlideStarted, turnaroundProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideFinished, turnaroundProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideFailed, turnaroundProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideTimedOut, turnaroundProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideReleased, turnaroundProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeCaseCreated, worklistProjector.HandleCaseCreated)
+	u.RegisterEventHandler(domain.EventTypeCaseAssigned, worklistProjector.HandleCaseAssigned)
+	u.RegisterEventHandler(domain.EventTypeCaseSignedOut, worklistProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseHeld, worklistProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseHoldReleased, worklistProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseCancelled, worklistProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseReopened, worklistProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeSlideCreated, worklistProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideStarted, worklistProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideFinished, worklistProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideFailed, worklistProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideTimedOut, worklistProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideReleased, worklistProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeSlideMoved, caseProjector.HandleSlideMoved)
+	u.RegisterEventHandler(domain.EventTypeSlideMoved, turnaroundProjector.HandleSlideMoved)
+	u.RegisterEventHandler(domain.EventTypeSlideMoved, worklistProjector.HandleSlideMoved)
+	u.RegisterEventHandler(domain.EventTypeCaseMerged, caseProjector.HandleCaseMerged)
+	u.RegisterEventHandler(domain.EventTypeCaseMerged, worklistProjector.HandleCaseStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeSlideDeleted, caseProjector.HandleSlideDeleted)
+	u.RegisterEventHandler(domain.EventTypeSlideRestored, caseProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeCaseDeleted, caseProjector.HandleCaseDeletionChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseRestored, caseProjector.HandleCaseDeletionChanged)
+	u.RegisterEventHandler(domain.EventTypeCasePurged, caseProjector.HandleCasePurged)
+	u.RegisterEventHandler(domain.EventTypeSlideDeleted, turnaroundProjector.HandleSlideDeletionChanged)
+	u.RegisterEventHandler(domain.EventTypeSlideRestored, turnaroundProjector.HandleSlideDeletionChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseDeleted, turnaroundProjector.HandleCaseDeletionChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseRestored, turnaroundProjector.HandleCaseDeletionChanged)
+	u.RegisterEventHandler(domain.EventTypeSlidePurged, turnaroundProjector.HandlePurged)
+	u.RegisterEventHandler(domain.EventTypeCasePurged, turnaroundProjector.HandlePurged)
+	u.RegisterEventHandler(domain.EventTypeSlideDeleted, worklistProjector.HandleSlideDeleted)
+	u.RegisterEventHandler(domain.EventTypeSlideRestored, worklistProjector.HandleSlideUpdated)
+	u.RegisterEventHandler(domain.EventTypeCaseDeleted, worklistProjector.HandleCaseDeletionChanged)
+	u.RegisterEventHandler(domain.EventTypeCaseRestored, worklistProjector.HandleCaseDeletionChanged)
+	u.RegisterEventHandler(domain.EventTypeCasePurged, worklistProjector.HandleCasePurged)
+	u.RegisterEventHandler(domain.EventTypeCasePreparationStatusChanged, caseProjector.HandleCasePreparationStatusChanged)
+	u.RegisterEventHandler(domain.EventTypeCasePreparationStatusChanged, worklistProjector.HandleCasePreparationStatusChanged)
+}
+
+// startWorkers starts the outbox processor and the periodic jobs.
+func (a *app) startWorkers() {
+	cfg := a.cfg
+	u := a.usecases
+
+	u.RegisterEventHandler(domain.EventTypeSlideTimedOut, a.alertSlideTimedOut)
+
+	u.StartEventsProcessor(cfg.Outbox.PollInterval)
+	u.StartEventsPruner(cfg.Outbox.PruneInterval, application.EventsRetention{
+		Archive: cfg.Outbox.ArchiveAfter,
+		Delete:  cfg.Outbox.DeleteAfter,
+	})
+	u.StartStuckSlideDetector(cfg.StuckSlides.CheckInterval, domain.SlideTimeouts{
+		Default: cfg.StuckSlides.Timeout,
+		ByStain: map[domain.StainType]time.Duration{
+			domain.StainTypeHE:      cfg.StuckSlides.HETimeout,
+			domain.StainTypeIHC:     cfg.StuckSlides.IHCTimeout,
+			domain.StainTypeSpecial: cfg.StuckSlides.SpecialTimeout,
+		},
+	})
+	u.StartLeaseReaper(cfg.WorkQueue.ReaperInterval)
+	u.StartPurger(cfg.Deletion.PurgeInterval, domain.DeletionPolicy{
+		RestoreGrace: cfg.Deletion.RestoreGrace,
+		Retention:    cfg.Deletion.Retention,
+	})
+}
+
+func (a *app) alertSlideTimedOut(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventSlideTimedOut)
+	if !ok {
+		return nil
+	}
+
+	a.logger.ErrorContext(ctx, "ALERT: slide timed out", "slide_id", e.SlideID, "case_id", e.CaseID, "reason", e.Reason)
+	return nil
+}
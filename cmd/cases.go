@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
+)
+
+func runCaseCreate(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("case create", flag.ContinueOnError)
+	priority := fs.String("priority", "routine", "case priority: routine, urgent or stat")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	p, err := parseName("priority", *priority, casePriorityNames)
+	if err != nil {
+		return err
+	}
+
+	caseID, err := a.usecases.CreateCase(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return showCase(ctx, a, caseID.String(), *output)
+}
+
+func runCaseShow(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("case show", flag.ContinueOnError)
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("expected a single case id")
+	}
+
+	return showCase(ctx, a, fs.Arg(0), *output)
+}
+
+// showCase reads from the primary, since most callers show a case they have
+// just changed.
+func showCase(ctx context.Context, a *app, rawID, output string) error {
+	caseID, err := parseID("case", rawID)
+	if err != nil {
+		return err
+	}
+	ctx = psqlclient.WithPrimary(ctx)
+
+	c, err := a.usecases.GetCase(ctx, caseID)
+	if err != nil {
+		return err
+	}
+	slides, err := a.usecases.GetCaseSlides(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(os.Stdout, output, toCaseView(c, slides))
+}
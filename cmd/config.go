@@ -0,0 +1,14 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+func runConfigPrint(_ context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: config print")
+	}
+	return a.cfg.WriteYAML(os.Stdout)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+func runEventsList(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("events list", flag.ContinueOnError)
+	pending := fs.Bool("pending", false, "list only events not yet published")
+	rawAfter := fs.String("after", "", "list events created after this RFC 3339 time")
+	limit := fs.Int("limit", 50, "maximum number of events")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	var after time.Time
+	if *rawAfter != "" {
+		t, err := time.Parse(time.RFC3339, *rawAfter)
+		if err != nil {
+			return fmt.Errorf("parse after: %w", err)
+		}
+		after = t
+	}
+
+	var (
+		events []domain.Event
+		err    error
+	)
+	if *pending {
+		events, err = a.usecases.ListUnpublishedEvents(ctx, *limit)
+	} else {
+		events, err = a.usecases.ListEvents(ctx, after, *limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(os.Stdout, *output, toEventsView(events))
+}
+
+// runProjectionRebuild drops the read models and replays the event history
+// into them. Run it while no worker is processing the outbox. With
+// outbox.delete_after set the archive is pruned and the history is partial,
+// so the rebuild is refused unless -partial is given.
+func runProjectionRebuild(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("projection rebuild", flag.ContinueOnError)
+	partial := fs.Bool("partial", false, "rebuild even though pruned events are missing from the history")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if a.cfg.Outbox.DeleteAfter > 0 && !*partial {
+		return fmt.Errorf("outbox.delete_after is %s, so events older than that are pruned and the read models would be rebuilt from partial history; pass -partial to rebuild anyway", a.cfg.Outbox.DeleteAfter)
+	}
+	if a.cfg.Outbox.DeleteAfter > 0 {
+		a.logger.WarnContext(ctx, "rebuilding read models from partial history", "delete_after", a.cfg.Outbox.DeleteAfter)
+	}
+
+	resets := []func(context.Context) error{
+		a.caseProjector.Reset,
+		a.turnaroundProjector.Reset,
+		a.worklistProjector.Reset,
+	}
+	for _, reset := range resets {
+		if err := reset(ctx); err != nil {
+			return err
+		}
+	}
+
+	n, err := a.usecases.ReplayEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("replay events after %d: %w", n, err)
+	}
+
+	a.logger.InfoContext(ctx, "events replayed", "count", n)
+	return nil
+}
@@ -2,56 +2,145 @@ package main
 
 import (
 	"context"
-	"time"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
-	"github.com/google/uuid"
-	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/config"
-	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
-	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/projection"
-	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/psql"
-	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/logging"
 )
 
-func main() {
-	cfg := config.MustLoad()
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, a *app, args []string) error
+}
 
-	db := psqlclient.MustNew(psqlclient.Config{
-		Username: cfg.PSQL.User,
-		Password: cfg.PSQL.Password,
-		Host:     cfg.PSQL.Host,
-		Port:     cfg.PSQL.Port,
-		Database: cfg.PSQL.DB,
-	})
-	mustMigrateUp(db)
+var commands = []command{
+	{"serve", "[-worker=true] run the HTTP API (postgres only)", runServe},
+	{"worker", "run the outbox processor and periodic jobs", runWorker},
+	{"migrate", "up|down|status|redo|version", runMigrate},
+	{"case create", "[-priority routine|urgent|stat] create a case", runCaseCreate},
+	{"case show", "<case-id> show a case with its specimens and slides", runCaseShow},
+	{"specimen add", "-case ID [-description TEXT] add a specimen to a case", runSpecimenAdd},
+	{"block add", "-case ID -specimen ID add a block to a specimen", runBlockAdd},
+	{"slide add", "-case ID -block ID -barcode CODE [-stain he|ihc|special] [-stain-name NAME] [-level N] add a slide", runSlideAdd},
+	{"slide start", "<slide-id> start preparing a slide", runSlideStart},
+	{"slide finish", "<slide-id> mark a slide as prepared", runSlideFinish},
+	{"events list", "[-pending] [-after TIME] [-limit N] list stored events", runEventsList},
+	{"projection rebuild", "[-partial] rebuild the read models from the event history (postgres only)", runProjectionRebuild},
+	{"config print", "print the effective config with secrets redacted", runConfigPrint},
+}
 
-	storage := psql.NewStorage(db)
+// offline commands get an app with only the config set, so they work without
+// a database.
+var offline = map[string]bool{
+	"config print": true,
+}
 
-	service := domain.NewService()
+// readModelCommands need the projections, which only the postgres storage
+// driver keeps.
+var readModelCommands = map[string]bool{
+	"serve":              true,
+	"projection rebuild": true,
+}
 
-	caseProjector := projection.NewCaseProjectior(db, service)
-	usecases := application.NewUsecases(storage, service)
-	usecases.RegisterEventHandler(domain.EventTypeSlideCreated, caseProjector.HandleSlideCreated)
-	usecases.RegisterEventHandler(domain.EventTypeSlideFinished, caseProjector.HandleSlideUpdated)
+func main() {
+	global := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	global.Usage = printUsage
+	configFile := global.String("config", "", "")
+	var overrides listFlag
+	global.Var(&overrides, "set", "")
+	if err := global.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
 
-	usecases.StartEventsProcessor(5 * time.Second)
+	cmd, args, ok := findCommand(global.Args())
+	if !ok {
+		printUsage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// err := usecases.CreateCase(context.Background())
-	// if err != nil {
-	// 	panic(err)
-	// }
+	cfg, err := config.Load(config.Sources{File: *configFile, Overrides: overrides})
+	if err != nil {
+		log.Fatalf("load config:\n%v", err)
+	}
+	if readModelCommands[cmd.name] {
+		if err := cfg.ValidateReadModels(); err != nil {
+			log.Fatalf("%s: %v", cmd.name, err)
+		}
+	}
 
-	slideID, _ := uuid.Parse("122c9557-784c-4fb2-a890-6f5a333505fb")
-	err := usecases.AddSlide(context.Background(), slideID)
+	logger, err := logging.New(os.Stderr, logging.Config{Level: cfg.Log.Level, Format: cfg.Log.Format})
 	if err != nil {
-		panic(err)
+		log.Fatalf("create logger: %v", err)
+	}
+	// Output of the log package, such as that of goose, goes through the
+	// same handler from here on.
+	slog.SetDefault(logger)
+
+	a := &app{cfg: cfg, logger: logger}
+	if !offline[cmd.name] {
+		if a, err = newApp(ctx, cfg, logger); err != nil {
+			fail(logger, cmd.name, err)
+		}
+	}
+	if err := cmd.run(ctx, a, args); err != nil && !errors.Is(err, flag.ErrHelp) {
+		fail(logger, cmd.name, err)
 	}
+}
 
-	// slideID, _ := uuid.Parse("2cbf1bb7-5bb3-44a8-aae0-ef3a68f4a96c")
-	// err := usecases.FinishSlide(context.Background(), slideID)
-	// if err != nil {
-	// 	panic(err)
-	// }
+// fail logs at error level, since log.Fatalf goes through the default slog
+// handler at info level and would be dropped with a higher log.level.
+func fail(logger *slog.Logger, command string, err error) {
+	logger.Error(command+" failed", "error", err)
+	os.Exit(1)
+}
+
+// findCommand matches the longest command name at the start of args.
+func findCommand(args []string) (command, []string, bool) {
+	for _, n := range []int{2, 1} {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		for _, cmd := range commands {
+			if cmd.name == name {
+				return cmd, args[n:], true
+			}
+		}
+	}
+	return command{}, nil, false
+}
 
-	time.Sleep(50 * time.Second)
+// listFlag collects every value of a repeated flag.
+type listFlag []string
+
+func (f *listFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: %s [-config FILE] [-set key=value]... <command> [flags]\n\n", os.Args[0])
+	fmt.Fprintln(os.Stderr, "config is read from defaults, the -config file (CONFIG_FILE), env vars and -set, in that order")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-20s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nmost commands accept -output json|table")
+	fmt.Fprintln(os.Stderr, "the sqlite storage driver keeps the write model only, so commands marked postgres only refuse to start on it")
 }
@@ -1,19 +1,52 @@
 package main
 
 import (
-	"log"
+	"fmt"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 	"github.com/pressly/goose/v3"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/config"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/migrations"
 )
 
-func mustMigrateUp(db *sqlx.DB) {
-	if err := goose.SetDialect("postgres"); err != nil {
-		log.Fatalf("goose set dialect: %v", err)
+func migrateUp(db *sqlx.DB, driver string) error {
+	if err := migrate(db, driver, "up"); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	return nil
+}
+
+// migrate runs a goose command against the embedded migrations of the
+// storage driver.
+func migrate(db *sqlx.DB, driver, command string) error {
+	dialect, fsys, dir := "postgres", migrations.FS, "."
+	if driver == config.StorageDriverSQLite {
+		dialect, fsys, dir = "sqlite3", migrations.SQLiteFS, "sqlite"
 	}
 
-	if err := goose.Up(db.DB, "./internal/catalog/migrations"); err != nil {
-		log.Fatalf("goose up: %v", err)
+	goose.SetBaseFS(fsys)
+	if err := goose.SetDialect(dialect); err != nil {
+		return fmt.Errorf("goose set dialect: %w", err)
+	}
+
+	var err error
+	switch command {
+	case "up":
+		err = goose.Up(db.DB, dir)
+	case "down":
+		err = goose.Down(db.DB, dir)
+	case "status":
+		err = goose.Status(db.DB, dir)
+	case "redo":
+		err = goose.Redo(db.DB, dir)
+	case "version":
+		err = goose.Version(db.DB, dir)
+	default:
+		return fmt.Errorf("unknown migrate command %q, want up|down|status|redo|version", command)
+	}
+	if err != nil {
+		return fmt.Errorf("goose %s: %w", command, err)
 	}
+	return nil
 }
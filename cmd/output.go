@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+var (
+	caseStatusNames = map[domain.CaseStatus]string{
+		domain.CaseStatusOpen:      "open",
+		domain.CaseStatusOnHold:    "on_hold",
+		domain.CaseStatusSignedOut: "signed_out",
+		domain.CaseStatusCancelled: "cancelled",
+		domain.CaseStatusMerged:    "merged",
+	}
+	casePriorityNames = map[domain.CasePriority]string{
+		domain.CasePriorityRoutine: "routine",
+		domain.CasePriorityUrgent:  "urgent",
+		domain.CasePriorityStat:    "stat",
+	}
+	slideStatusNames = map[domain.SlidePreparationStatus]string{
+		domain.SlidePreparationStatusNotStarted: "not_started",
+		domain.SlidePreparationStatusProcessing: "processing",
+		domain.SlidePreparationStatusDone:       "done",
+		domain.SlidePreparationStatusError:      "error",
+	}
+	stainTypeNames = map[domain.StainType]string{
+		domain.StainTypeHE:      "he",
+		domain.StainTypeIHC:     "ihc",
+		domain.StainTypeSpecial: "special",
+	}
+)
+
+func parseName[T comparable](kind, raw string, names map[T]string) (T, error) {
+	for value, name := range names {
+		if name == raw {
+			return value, nil
+		}
+	}
+	var zero T
+	return zero, fmt.Errorf("unknown %s %q", kind, raw)
+}
+
+func parseID(kind, raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("parse %s id: %w", kind, err)
+	}
+	return id, nil
+}
+
+// tabular is a command result that can be printed as a table.
+type tabular interface {
+	table() (header []string, rows [][]string)
+}
+
+func outputFlag(fs *flag.FlagSet) *string {
+	return fs.String("output", "table", "output format: json or table")
+}
+
+func checkOutput(format string) error {
+	if format != "json" && format != "table" {
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	return nil
+}
+
+func writeOutput(w io.Writer, format string, v tabular) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	header, rows := v.table()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+type caseView struct {
+	ID          uuid.UUID      `json:"id"`
+	Status      string         `json:"status"`
+	Priority    string         `json:"priority"`
+	Pathologist string         `json:"pathologist,omitempty"`
+	MergedInto  *uuid.UUID     `json:"merged_into,omitempty"`
+	DeletedAt   *time.Time     `json:"deleted_at,omitempty"`
+	Specimens   []specimenView `json:"specimens"`
+	Slides      []slideView    `json:"slides"`
+}
+
+type specimenView struct {
+	ID          uuid.UUID   `json:"id"`
+	Label       string      `json:"label"`
+	Description string      `json:"description"`
+	Blocks      []blockView `json:"blocks"`
+}
+
+type blockView struct {
+	ID    uuid.UUID `json:"id"`
+	Label string    `json:"label"`
+}
+
+func toCaseView(c domain.Case, slides []domain.Slide) caseView {
+	v := caseView{
+		ID:          c.ID,
+		Status:      caseStatusNames[c.Status],
+		Priority:    casePriorityNames[c.Priority],
+		Pathologist: c.Pathologist,
+		DeletedAt:   optionalTime(c.DeletedAt),
+		Specimens:   make([]specimenView, 0, len(c.Specimens)),
+		Slides:      make([]slideView, 0, len(slides)),
+	}
+	if c.MergedInto != uuid.Nil {
+		v.MergedInto = &c.MergedInto
+	}
+
+	for _, sp := range c.Specimens {
+		spv := specimenView{
+			ID:          sp.ID,
+			Label:       sp.Label,
+			Description: sp.Description,
+			Blocks:      make([]blockView, 0, len(sp.Blocks)),
+		}
+		for _, b := range sp.Blocks {
+			spv.Blocks = append(spv.Blocks, blockView{ID: b.ID, Label: b.Label})
+		}
+		v.Specimens = append(v.Specimens, spv)
+	}
+
+	for _, s := range slides {
+		v.Slides = append(v.Slides, toSlideView(s))
+	}
+
+	return v
+}
+
+func (v caseView) table() ([]string, [][]string) {
+	rows := [][]string{
+		{"id", v.ID.String()},
+		{"status", v.Status},
+		{"priority", v.Priority},
+		{"pathologist", v.Pathologist},
+	}
+	if v.MergedInto != nil {
+		rows = append(rows, []string{"merged into", v.MergedInto.String()})
+	}
+	if v.DeletedAt != nil {
+		rows = append(rows, []string{"deleted at", formatTime(*v.DeletedAt)})
+	}
+	for _, sp := range v.Specimens {
+		rows = append(rows, []string{"specimen " + sp.Label, sp.ID.String() + " " + sp.Description})
+		for _, b := range sp.Blocks {
+			rows = append(rows, []string{"block " + b.Label, b.ID.String()})
+		}
+	}
+	for _, s := range v.Slides {
+		rows = append(rows, []string{"slide " + s.Label, strings.Join([]string{s.ID.String(), s.Barcode, s.Status, s.Stain}, " ")})
+	}
+	return []string{"FIELD", "VALUE"}, rows
+}
+
+func (v specimenView) table() ([]string, [][]string) {
+	rows := [][]string{
+		{"id", v.ID.String()},
+		{"label", v.Label},
+		{"description", v.Description},
+	}
+	for _, b := range v.Blocks {
+		rows = append(rows, []string{"block " + b.Label, b.ID.String()})
+	}
+	return []string{"FIELD", "VALUE"}, rows
+}
+
+func (v blockView) table() ([]string, [][]string) {
+	return []string{"FIELD", "VALUE"}, [][]string{
+		{"id", v.ID.String()},
+		{"label", v.Label},
+	}
+}
+
+type slideView struct {
+	ID            uuid.UUID  `json:"id"`
+	CaseID        uuid.UUID  `json:"case_id"`
+	BlockID       uuid.UUID  `json:"block_id"`
+	Barcode       string     `json:"barcode"`
+	Label         string     `json:"label"`
+	Stain         string     `json:"stain"`
+	StainName     string     `json:"stain_name,omitempty"`
+	Level         int        `json:"level"`
+	Status        string     `json:"status"`
+	AssignedTo    string     `json:"assigned_to,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	FailedAt      *time.Time `json:"failed_at,omitempty"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+}
+
+func toSlideView(s domain.Slide) slideView {
+	return slideView{
+		ID:            s.ID,
+		CaseID:        s.CaseID,
+		BlockID:       s.BlockID,
+		Barcode:       s.Barcode,
+		Label:         s.Label,
+		Stain:         stainTypeNames[s.Stain.Type],
+		StainName:     s.Stain.Name,
+		Level:         s.Level,
+		Status:        slideStatusNames[s.PreparationStatus],
+		AssignedTo:    s.AssignedTo,
+		CreatedAt:     s.CreatedAt,
+		StartedAt:     optionalTime(s.StartedAt),
+		FinishedAt:    optionalTime(s.FinishedAt),
+		FailedAt:      optionalTime(s.FailedAt),
+		FailureReason: s.FailureReason,
+	}
+}
+
+func (v slideView) table() ([]string, [][]string) {
+	rows := [][]string{
+		{"id", v.ID.String()},
+		{"case", v.CaseID.String()},
+		{"block", v.BlockID.String()},
+		{"barcode", v.Barcode},
+		{"label", v.Label},
+		{"stain", strings.TrimSpace(v.Stain + " " + v.StainName)},
+		{"level", fmt.Sprint(v.Level)},
+		{"status", v.Status},
+		{"assigned to", v.AssignedTo},
+		{"created at", formatTime(v.CreatedAt)},
+	}
+	for _, t := range []struct {
+		name string
+		at   *time.Time
+	}{{"started at", v.StartedAt}, {"finished at", v.FinishedAt}, {"failed at", v.FailedAt}} {
+		if t.at != nil {
+			rows = append(rows, []string{t.name, formatTime(*t.at)})
+		}
+	}
+	if v.FailureReason != "" {
+		rows = append(rows, []string{"failure reason", v.FailureReason})
+	}
+	return []string{"FIELD", "VALUE"}, rows
+}
+
+type eventView struct {
+	ID        uuid.UUID    `json:"id"`
+	Type      string       `json:"type"`
+	CreatedAt time.Time    `json:"created_at"`
+	Payload   domain.Event `json:"payload"`
+}
+
+type eventsView []eventView
+
+func toEventsView(events []domain.Event) eventsView {
+	v := make(eventsView, 0, len(events))
+	for _, e := range events {
+		v = append(v, eventView{
+			ID:        e.EventID(),
+			Type:      e.Name(),
+			CreatedAt: e.CreatedAt(),
+			Payload:   e,
+		})
+	}
+	return v
+}
+
+func (v eventsView) table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(v))
+	for _, e := range v {
+		rows = append(rows, []string{e.ID.String(), e.Type, formatTime(e.CreatedAt)})
+	}
+	return []string{"ID", "TYPE", "CREATED AT"}, rows
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format(time.RFC3339)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/httpapi"
+)
+
+func runServe(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	withWorker := fs.Bool("worker", true, "also run the outbox processor and periodic jobs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if a.cfg.Migrations.AutoMigrate {
+		if err := migrateUp(a.db, a.cfg.Storage.Driver); err != nil {
+			return err
+		}
+	}
+	if *withWorker {
+		a.startWorkers()
+	}
+
+	server := &http.Server{
+		Addr:              a.cfg.HTTP.Addr,
+		Handler:           httpapi.NewHandler(a.queries, a.logger),
+		ReadHeaderTimeout: a.cfg.HTTP.ReadHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), a.cfg.HTTP.ShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			a.logger.Error("shutdown http server failed", "error", err)
+		}
+	}()
+
+	a.logger.Info("http server listening", "addr", a.cfg.HTTP.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("listen and serve: %w", err)
+	}
+	return nil
+}
+
+func runWorker(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("worker", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if a.cfg.Migrations.AutoMigrate {
+		if err := migrateUp(a.db, a.cfg.Storage.Driver); err != nil {
+			return err
+		}
+	}
+	a.startWorkers()
+
+	a.logger.Info("worker started")
+	<-ctx.Done()
+	return nil
+}
+
+func runMigrate(_ context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: migrate up|down|status|redo|version")
+	}
+	return migrate(a.db, a.cfg.Storage.Driver, args[0])
+}
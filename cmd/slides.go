@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+func runSlideAdd(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("slide add", flag.ContinueOnError)
+	rawCaseID := fs.String("case", "", "case id")
+	rawBlockID := fs.String("block", "", "block id")
+	barcode := fs.String("barcode", "", "slide barcode")
+	stain := fs.String("stain", "he", "stain type: he, ihc or special")
+	stainName := fs.String("stain-name", "", "IHC marker or special stain name")
+	level := fs.Int("level", 1, "section level")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	caseID, err := parseID("case", *rawCaseID)
+	if err != nil {
+		return err
+	}
+	blockID, err := parseID("block", *rawBlockID)
+	if err != nil {
+		return err
+	}
+	stainType, err := parseName("stain", *stain, stainTypeNames)
+	if err != nil {
+		return err
+	}
+
+	err = a.usecases.AddSlide(ctx, caseID, domain.SlideSpec{
+		Stain:   domain.Stain{Type: stainType, Name: *stainName},
+		BlockID: blockID,
+		Level:   *level,
+		Barcode: *barcode,
+	})
+	if err != nil {
+		return err
+	}
+
+	slide, err := a.usecases.GetSlideByBarcode(ctx, *barcode)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(os.Stdout, *output, toSlideView(slide))
+}
+
+func runSlideStart(ctx context.Context, a *app, args []string) error {
+	return runSlideTransition(ctx, a, "slide start", args, a.usecases.StartSlide)
+}
+
+func runSlideFinish(ctx context.Context, a *app, args []string) error {
+	return runSlideTransition(ctx, a, "slide finish", args, a.usecases.FinishSlide)
+}
+
+// runSlideTransition applies a status change to the slide given as the only
+// argument and prints the slide.
+func runSlideTransition(
+	ctx context.Context,
+	a *app,
+	name string,
+	args []string,
+	apply func(ctx context.Context, slideID uuid.UUID) error,
+) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("expected a single slide id")
+	}
+
+	slideID, err := parseID("slide", fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if err := apply(ctx, slideID); err != nil {
+		return err
+	}
+
+	slide, err := a.usecases.GetSlide(ctx, slideID)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(os.Stdout, *output, toSlideView(slide))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
+)
+
+func runSpecimenAdd(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("specimen add", flag.ContinueOnError)
+	rawCaseID := fs.String("case", "", "case id")
+	description := fs.String("description", "", "specimen description")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	caseID, err := parseID("case", *rawCaseID)
+	if err != nil {
+		return err
+	}
+
+	specimenID, err := a.usecases.AddSpecimen(ctx, caseID, *description)
+	if err != nil {
+		return err
+	}
+
+	specimen, err := findSpecimen(ctx, a, caseID, specimenID)
+	if err != nil {
+		return err
+	}
+	return writeOutput(os.Stdout, *output, specimen)
+}
+
+func runBlockAdd(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("block add", flag.ContinueOnError)
+	rawCaseID := fs.String("case", "", "case id")
+	rawSpecimenID := fs.String("specimen", "", "specimen id")
+	output := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	caseID, err := parseID("case", *rawCaseID)
+	if err != nil {
+		return err
+	}
+	specimenID, err := parseID("specimen", *rawSpecimenID)
+	if err != nil {
+		return err
+	}
+
+	blockID, err := a.usecases.AddBlock(ctx, caseID, specimenID)
+	if err != nil {
+		return err
+	}
+
+	specimen, err := findSpecimen(ctx, a, caseID, specimenID)
+	if err != nil {
+		return err
+	}
+	for _, b := range specimen.Blocks {
+		if b.ID == blockID {
+			return writeOutput(os.Stdout, *output, b)
+		}
+	}
+	return fmt.Errorf("block %s not found after adding it", blockID)
+}
+
+// findSpecimen reads the case from the primary, since the specimen has just
+// been changed.
+func findSpecimen(ctx context.Context, a *app, caseID, specimenID uuid.UUID) (specimenView, error) {
+	c, err := a.usecases.GetCase(psqlclient.WithPrimary(ctx), caseID)
+	if err != nil {
+		return specimenView{}, err
+	}
+
+	for _, sp := range toCaseView(c, nil).Specimens {
+		if sp.ID == specimenID {
+			return sp, nil
+		}
+	}
+	return specimenView{}, fmt.Errorf("specimen %s not found in case %s", specimenID, caseID)
+}
@@ -0,0 +1,180 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+func (u *Usecases) DeleteSlide(ctx context.Context, slideID uuid.UUID, reason string) error {
+	return u.updateSlide(ctx, slideID, func(slide domain.Slide, caseSlides []domain.Slide) (domain.Slide, error) {
+		slide, err := u.service.DeleteSlide(slide, caseSlides, reason)
+		if err != nil {
+			return domain.Slide{}, fmt.Errorf("delete slide: %w", err)
+		}
+		return slide, nil
+	})
+}
+
+func (u *Usecases) RestoreSlide(ctx context.Context, slideID uuid.UUID, policy domain.DeletionPolicy) error {
+	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		slide, err := u.storage.GetDeletedSlide(ctx, slideID)
+		if err != nil {
+			return fmt.Errorf("get deleted slide: %w", err)
+		}
+
+		if _, err := u.storage.GetCase(ctx, slide.CaseID); err != nil {
+			return fmt.Errorf("get case: %w", err)
+		}
+
+		caseSlides, err := u.storage.GetSlidesByCaseID(ctx, slide.CaseID)
+		if err != nil {
+			return fmt.Errorf("get slides by id: %w", err)
+		}
+
+		slide, err = u.service.RestoreSlide(slide, caseSlides, policy)
+		if err != nil {
+			return fmt.Errorf("restore slide: %w", err)
+		}
+
+		if err := u.storage.AddEvent(ctx, slide.PullEvents()); err != nil {
+			return fmt.Errorf("add events: %w", err)
+		}
+
+		if err := u.storage.SaveSlide(ctx, slide); err != nil {
+			return fmt.Errorf("save slide: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteCase soft-deletes the case. Its slides are not touched: storage
+// hides the slides of deleted cases, so they come back with the case on
+// restore.
+func (u *Usecases) DeleteCase(ctx context.Context, caseID uuid.UUID, reason string) error {
+	return u.updateCase(ctx, caseID, func(c *domain.Case) error {
+		if err := c.Delete(u.env, reason); err != nil {
+			return fmt.Errorf("delete case: %w", err)
+		}
+		return nil
+	})
+}
+
+func (u *Usecases) RestoreCase(ctx context.Context, caseID uuid.UUID, policy domain.DeletionPolicy) error {
+	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		c, err := u.storage.GetDeletedCase(ctx, caseID)
+		if err != nil {
+			return fmt.Errorf("get deleted case: %w", err)
+		}
+
+		if err := c.Restore(u.env, policy); err != nil {
+			return fmt.Errorf("restore case: %w", err)
+		}
+
+		if err := u.storage.SaveCase(ctx, c); err != nil {
+			return fmt.Errorf("save case: %w", err)
+		}
+
+		if err := u.storage.AddEvent(ctx, c.PullEvents()); err != nil {
+			return fmt.Errorf("add events: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// PurgeDeleted removes slides and cases deleted longer than the retention
+// period ago. Only a tombstone event is left behind for each of them. A case
+// that others were merged into is kept and reported with
+// domain.ErrCaseHasMergedCases.
+func (u *Usecases) PurgeDeleted(ctx context.Context, policy domain.DeletionPolicy) error {
+	before := u.env.Clock.Now().Add(-policy.Retention)
+
+	slides, err := u.storage.GetSlidesDeletedBefore(ctx, before)
+	if err != nil {
+		return fmt.Errorf("get deleted slides: %w", err)
+	}
+
+	caseIDs, err := u.storage.GetCaseIDsDeletedBefore(ctx, before)
+	if err != nil {
+		return fmt.Errorf("get deleted cases: %w", err)
+	}
+
+	var errs []error
+	for _, slide := range slides {
+		if err := u.purgeSlide(ctx, slide.ID, policy); err != nil {
+			errs = append(errs, fmt.Errorf("purge slide %s: %w", slide.ID, err))
+			continue
+		}
+		u.logger.InfoContext(ctx, "slide purged", "slide_id", slide.ID, "case_id", slide.CaseID)
+	}
+	for _, caseID := range caseIDs {
+		if err := u.purgeCase(ctx, caseID, policy); err != nil {
+			errs = append(errs, fmt.Errorf("purge case %s: %w", caseID, err))
+			continue
+		}
+		u.logger.InfoContext(ctx, "case purged", "case_id", caseID)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (u *Usecases) StartPurger(interval time.Duration, policy domain.DeletionPolicy) {
+	runEvery(interval, func(ctx context.Context) {
+		if err := u.PurgeDeleted(ctx, policy); err != nil {
+			u.logger.ErrorContext(ctx, "purging deleted slides and cases failed", "error", err)
+		}
+	})
+}
+
+func (u *Usecases) purgeSlide(ctx context.Context, slideID uuid.UUID, policy domain.DeletionPolicy) error {
+	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		slide, err := u.storage.GetDeletedSlide(ctx, slideID)
+		if err != nil {
+			return fmt.Errorf("get deleted slide: %w", err)
+		}
+
+		slide, err = u.service.PurgeSlide(slide, policy)
+		if err != nil {
+			return err
+		}
+
+		if err := u.storage.AddEvent(ctx, slide.PullEvents()); err != nil {
+			return fmt.Errorf("add events: %w", err)
+		}
+
+		if err := u.storage.DeleteSlide(ctx, slide); err != nil {
+			return fmt.Errorf("delete slide: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (u *Usecases) purgeCase(ctx context.Context, caseID uuid.UUID, policy domain.DeletionPolicy) error {
+	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		c, err := u.storage.GetDeletedCase(ctx, caseID)
+		if err != nil {
+			return fmt.Errorf("get deleted case: %w", err)
+		}
+
+		if err := c.Purge(u.env, policy); err != nil {
+			return err
+		}
+
+		if err := u.storage.AddEvent(ctx, c.PullEvents()); err != nil {
+			return fmt.Errorf("add events: %w", err)
+		}
+
+		if err := u.storage.DeleteCase(ctx, c); err != nil {
+			return fmt.Errorf("delete case: %w", err)
+		}
+
+		return nil
+	})
+}
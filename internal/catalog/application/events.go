@@ -3,22 +3,42 @@ package application
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/logging"
 )
 
-const maxUnpublishedEventsToFetch = 10
+const (
+	maxUnpublishedEventsToFetch = 10
+	eventsArchiveBatch          = 1000
+	eventsReplayBatch           = 1000
+)
+
+// EventsRetention configures how long published events stay in the outbox.
+// They are moved to the archive after Archive and dropped from it after
+// Delete. A zero Delete keeps archived events forever.
+type EventsRetention struct {
+	Archive time.Duration
+	Delete  time.Duration
+}
 
 type EventHandler func(ctx context.Context, e domain.Event) error
 
 type eventsProcessor struct {
 	storage  eventsStorage
+	clock    domain.Clock
+	logger   *slog.Logger
 	handlers map[domain.EventType][]EventHandler
 }
 
-func newEventsProcessor(storage eventsStorage) *eventsProcessor {
+func newEventsProcessor(storage eventsStorage, clock domain.Clock, logger *slog.Logger) *eventsProcessor {
 	return &eventsProcessor{
 		storage:  storage,
+		clock:    clock,
+		logger:   logger,
 		handlers: make(map[domain.EventType][]EventHandler),
 	}
 }
@@ -41,11 +61,17 @@ func (p *eventsProcessor) Process(ctx context.Context) error {
 		return fmt.Errorf("mark published: %w", err)
 	}
 
+	if len(events) > 0 {
+		p.logger.InfoContext(ctx, "events published", "count", len(events))
+	}
 	return nil
 }
 
 func (p *eventsProcessor) publish(ctx context.Context, events []domain.Event) error {
 	for _, e := range events {
+		ctx := logging.With(ctx, "event_id", e.EventID(), "event", e.Name())
+		p.logger.DebugContext(ctx, "publishing event", "handlers", len(p.handlers[e.EventType()]))
+
 		for _, handler := range p.handlers[e.EventType()] {
 			if err := handler(ctx, e); err != nil {
 				return fmt.Errorf("handle event %v: %w", e.Name(), err)
@@ -54,3 +80,55 @@ func (p *eventsProcessor) publish(ctx context.Context, events []domain.Event) er
 	}
 	return nil
 }
+
+// prune archives published events in batches, so a large backlog does not
+// hold locks on the outbox for long.
+func (p *eventsProcessor) prune(ctx context.Context, retention EventsRetention) error {
+	now := p.clock.Now()
+
+	for {
+		n, err := p.storage.ArchiveEvents(ctx, now.Add(-retention.Archive), eventsArchiveBatch)
+		if err != nil {
+			return fmt.Errorf("archive events: %w", err)
+		}
+		if n < eventsArchiveBatch {
+			break
+		}
+	}
+
+	if retention.Delete > 0 {
+		if _, err := p.storage.PruneArchivedEvents(ctx, now.Add(-retention.Delete)); err != nil {
+			return fmt.Errorf("prune archived events: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// replay feeds every stored event, archived ones included, to the handlers in
+// creation order. Events dropped from the archive are lost to the replay.
+func (p *eventsProcessor) replay(ctx context.Context) (int, error) {
+	var (
+		after   time.Time
+		afterID uuid.UUID
+		total   int
+	)
+
+	for {
+		events, err := p.storage.FetchEvents(ctx, after, afterID, eventsReplayBatch)
+		if err != nil {
+			return total, fmt.Errorf("fetch events: %w", err)
+		}
+
+		if err := p.publish(ctx, events); err != nil {
+			return total, fmt.Errorf("publish event: %w", err)
+		}
+		total += len(events)
+
+		if len(events) < eventsReplayBatch {
+			return total, nil
+		}
+		last := events[len(events)-1]
+		after, afterID = last.CreatedAt(), last.EventID()
+	}
+}
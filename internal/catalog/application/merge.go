@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+// GetCase returns the case by ID. A merged case resolves to the case it was
+// merged into, following the chain if that one was merged as well. The chain
+// is read from a single snapshot, so a concurrent merge cannot break it.
+// Inside a writing transaction it is read within that one at its isolation
+// level instead; the version checks of its writes catch concurrent changes.
+func (u *Usecases) GetCase(ctx context.Context, caseID uuid.UUID) (domain.Case, error) {
+	var c domain.Case
+
+	opts := []TxOption{ReadOnly(), WithIsolation(IsolationRepeatableRead)}
+	if outer, ok := u.storage.ActiveTx(ctx); ok && !outer.ReadOnly {
+		opts = nil
+	}
+
+	err := u.storage.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		c, err = u.storage.GetCase(ctx, caseID)
+		if err != nil {
+			return fmt.Errorf("get case: %w", err)
+		}
+
+		for c.MergedInto != uuid.Nil {
+			c, err = u.storage.GetCase(ctx, c.MergedInto)
+			if err != nil {
+				return fmt.Errorf("get merged case: %w", err)
+			}
+		}
+		return nil
+	}, opts...)
+	if err != nil {
+		return domain.Case{}, err
+	}
+
+	return c, nil
+}
+
+func (u *Usecases) MergeCases(ctx context.Context, sourceID, targetID uuid.UUID) error {
+	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		source, sourceSlides, err := u.getCaseWithSlides(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+
+		target, targetSlides, err := u.getCaseWithSlides(ctx, targetID)
+		if err != nil {
+			return err
+		}
+
+		slides, err := u.service.MergeCases(&source, &target, sourceSlides, targetSlides)
+		if err != nil {
+			return fmt.Errorf("merge cases: %w", err)
+		}
+
+		return u.saveSlideMoves(ctx, target, source, slides)
+	})
+}
+
+// SplitCase moves the selected slides of the case into a new case and returns
+// the new case ID.
+func (u *Usecases) SplitCase(ctx context.Context, caseID uuid.UUID, slideIDs []uuid.UUID) (uuid.UUID, error) {
+	var newCaseID uuid.UUID
+
+	err := u.storage.WithTx(ctx, func(ctx context.Context) error {
+		source, sourceSlides, err := u.getCaseWithSlides(ctx, caseID)
+		if err != nil {
+			return err
+		}
+
+		target, slides, err := u.service.SplitCase(&source, sourceSlides, slideIDs)
+		if err != nil {
+			return fmt.Errorf("split case: %w", err)
+		}
+		newCaseID = target.ID
+
+		return u.saveSlideMoves(ctx, target, source, slides)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return newCaseID, nil
+}
+
+func (u *Usecases) getCaseWithSlides(ctx context.Context, caseID uuid.UUID) (domain.Case, []domain.Slide, error) {
+	c, err := u.storage.GetCase(ctx, caseID)
+	if err != nil {
+		return domain.Case{}, nil, fmt.Errorf("get case: %w", err)
+	}
+
+	slides, err := u.storage.GetSlidesByCaseID(ctx, caseID)
+	if err != nil {
+		return domain.Case{}, nil, fmt.Errorf("get slides by id: %w", err)
+	}
+
+	return c, slides, nil
+}
+
+// saveSlideMoves persists the target case before the slides, since moved
+// slides refer to blocks added to it. Events keep the same order: the target
+// case events come first, then the slide moves and finally the source case.
+func (u *Usecases) saveSlideMoves(ctx context.Context, target, source domain.Case, slides []domain.Slide) error {
+	if err := u.storage.SaveCase(ctx, target); err != nil {
+		return fmt.Errorf("save target case: %w", err)
+	}
+	if err := u.storage.SaveCase(ctx, source); err != nil {
+		return fmt.Errorf("save source case: %w", err)
+	}
+
+	events := target.PullEvents()
+	for _, slide := range slides {
+		if err := u.storage.SaveSlide(ctx, slide); err != nil {
+			return fmt.Errorf("save slide: %w", err)
+		}
+		events = append(events, slide.PullEvents()...)
+	}
+	events = append(events, source.PullEvents()...)
+
+	if err := u.storage.AddEvent(ctx, events); err != nil {
+		return fmt.Errorf("add events: %w", err)
+	}
+
+	return nil
+}
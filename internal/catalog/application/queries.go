@@ -0,0 +1,190 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+const (
+	defaultCaseListLimit = 50
+	maxCaseListLimit     = 200
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type CaseSort uint8
+
+const (
+	CaseSortCreatedAt CaseSort = iota
+	CaseSortUpdatedAt
+	CaseSortPriority
+)
+
+// CaseListQuery filters and orders the case listing. Zero values mean "no
+// restriction". Cursor is the NextCursor of the previous page.
+type CaseListQuery struct {
+	Status            domain.CaseStatus
+	PreparationStatus domain.CasePreparationStatus
+	Priority          domain.CasePriority
+	CreatedFrom       time.Time
+	CreatedTo         time.Time
+	Sort              CaseSort
+	Descending        bool
+	Cursor            string
+	Limit             int
+}
+
+type SlideCounts struct {
+	NotStarted int
+	Processing int
+	Done       int
+	Error      int
+}
+
+type CaseSummary struct {
+	ID                uuid.UUID
+	Status            domain.CaseStatus
+	PreparationStatus domain.CasePreparationStatus
+	Priority          domain.CasePriority
+	SlideCounts       SlideCounts
+	MergedInto        uuid.UUID
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+type CasePage struct {
+	Cases      []CaseSummary
+	NextCursor string
+}
+
+type caseReadModel interface {
+	ListCases(ctx context.Context, query CaseListQuery) (CasePage, error)
+	GetCase(ctx context.Context, caseID uuid.UUID) (CaseSummary, error)
+}
+
+// WorklistItem is a case assigned to a pathologist whose slides are all
+// prepared, so it is ready to be reviewed.
+type WorklistItem struct {
+	CaseID     uuid.UUID
+	Priority   domain.CasePriority
+	AssignedAt time.Time
+	ReadyAt    time.Time
+}
+
+type worklistReadModel interface {
+	Worklist(ctx context.Context, pathologist string) ([]WorklistItem, error)
+}
+
+// TurnaroundFilter narrows turnaround statistics down to slides finished in
+// [From, To). Zero values mean "no restriction".
+type TurnaroundFilter struct {
+	From      time.Time
+	To        time.Time
+	StainType domain.StainType
+	Priority  domain.CasePriority
+}
+
+type TurnaroundStats struct {
+	Count  int
+	Median time.Duration
+	P90    time.Duration
+}
+
+type CaseTurnaroundStats struct {
+	CaseID uuid.UUID
+	TurnaroundStats
+}
+
+type DailyTurnaroundStats struct {
+	Day time.Time
+	TurnaroundStats
+}
+
+type turnaroundReadModel interface {
+	CaseTurnaround(ctx context.Context, filter TurnaroundFilter) ([]CaseTurnaroundStats, error)
+	DailyTurnaround(ctx context.Context, filter TurnaroundFilter) ([]DailyTurnaroundStats, error)
+}
+
+func NewQueries(cases caseReadModel, turnarounds turnaroundReadModel, worklists worklistReadModel) *Queries {
+	return &Queries{
+		cases:       cases,
+		turnarounds: turnarounds,
+		worklists:   worklists,
+	}
+}
+
+type Queries struct {
+	cases       caseReadModel
+	turnarounds turnaroundReadModel
+	worklists   worklistReadModel
+}
+
+func (q *Queries) Worklist(ctx context.Context, pathologist string) ([]WorklistItem, error) {
+	if pathologist == "" {
+		return nil, domain.ErrPathologistRequired
+	}
+
+	items, err := q.worklists.Worklist(ctx, pathologist)
+	if err != nil {
+		return nil, fmt.Errorf("worklist: %w", err)
+	}
+
+	return items, nil
+}
+
+func (q *Queries) ListCases(ctx context.Context, query CaseListQuery) (CasePage, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultCaseListLimit
+	}
+	if query.Limit > maxCaseListLimit {
+		query.Limit = maxCaseListLimit
+	}
+
+	page, err := q.cases.ListCases(ctx, query)
+	if err != nil {
+		return CasePage{}, fmt.Errorf("list cases: %w", err)
+	}
+
+	return page, nil
+}
+
+// GetCase returns the case summary, resolving merged cases to the case they
+// were merged into.
+func (q *Queries) GetCase(ctx context.Context, caseID uuid.UUID) (CaseSummary, error) {
+	c, err := q.cases.GetCase(ctx, caseID)
+	if err != nil {
+		return CaseSummary{}, fmt.Errorf("get case: %w", err)
+	}
+
+	for c.MergedInto != uuid.Nil {
+		c, err = q.cases.GetCase(ctx, c.MergedInto)
+		if err != nil {
+			return CaseSummary{}, fmt.Errorf("get merged case: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+func (q *Queries) CaseTurnaround(ctx context.Context, filter TurnaroundFilter) ([]CaseTurnaroundStats, error) {
+	stats, err := q.turnarounds.CaseTurnaround(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("case turnaround: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (q *Queries) DailyTurnaround(ctx context.Context, filter TurnaroundFilter) ([]DailyTurnaroundStats, error) {
+	stats, err := q.turnarounds.DailyTurnaround(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("daily turnaround: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,274 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/logging"
+)
+
+// AddSlide creates a slide in the case. The case is saved as well, so its
+// version guards against a concurrent sign-out that has not seen the slide.
+func (u *Usecases) AddSlide(ctx context.Context, caseID uuid.UUID, spec domain.SlideSpec) error {
+	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		c, err := u.storage.GetCase(ctx, caseID)
+		if err != nil {
+			return fmt.Errorf("get case: %w", err)
+		}
+
+		caseSlides, err := u.storage.GetSlidesByCaseID(ctx, caseID)
+		if err != nil {
+			return fmt.Errorf("get slides by id: %w", err)
+		}
+
+		_, err = u.storage.GetSlideByBarcode(ctx, spec.Barcode)
+		if err == nil {
+			return domain.ErrSlideBarcodeTaken
+		}
+		if !errors.Is(err, domain.ErrSlideNotFound) {
+			return fmt.Errorf("get slide by barcode: %w", err)
+		}
+
+		slide, err := u.service.CreateSlide(c, caseSlides, spec)
+		if err != nil {
+			return fmt.Errorf("create slide: %w", err)
+		}
+
+		if err := u.storage.SaveCase(ctx, c); err != nil {
+			return fmt.Errorf("save case: %w", err)
+		}
+
+		if err := u.storage.SaveSlide(ctx, slide); err != nil {
+			return fmt.Errorf("save slide: %w", err)
+		}
+
+		if err := u.storage.AddEvent(ctx, slide.PullEvents()); err != nil {
+			return fmt.Errorf("add events: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// AddSlides creates all slides in a single transaction. Either every slide
+// is created or none of them. Like AddSlide, it saves the case too.
+func (u *Usecases) AddSlides(ctx context.Context, caseID uuid.UUID, specs []domain.SlideSpec) error {
+	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		c, err := u.storage.GetCase(ctx, caseID)
+		if err != nil {
+			return fmt.Errorf("get case: %w", err)
+		}
+
+		caseSlides, err := u.storage.GetSlidesByCaseID(ctx, caseID)
+		if err != nil {
+			return fmt.Errorf("get slides by id: %w", err)
+		}
+
+		barcodes := make([]string, 0, len(specs))
+		for _, spec := range specs {
+			barcodes = append(barcodes, spec.Barcode)
+		}
+
+		taken, err := u.storage.GetSlidesByBarcodes(ctx, barcodes)
+		if err != nil {
+			return fmt.Errorf("get slides by barcodes: %w", err)
+		}
+		if len(taken) > 0 {
+			return fmt.Errorf("slide %q: %w", taken[0].Barcode, domain.ErrSlideBarcodeTaken)
+		}
+
+		slides, err := u.service.CreateSlides(&c, caseSlides, specs)
+		if err != nil {
+			return fmt.Errorf("create slides: %w", err)
+		}
+
+		if err := u.storage.SaveCase(ctx, c); err != nil {
+			return fmt.Errorf("save case: %w", err)
+		}
+
+		if err := u.storage.SaveSlides(ctx, slides); err != nil {
+			return fmt.Errorf("save slides: %w", err)
+		}
+
+		var events []domain.Event
+		for i := range slides {
+			events = append(events, slides[i].PullEvents()...)
+		}
+		events = append(events, c.PullEvents()...)
+
+		if err := u.storage.AddEvent(ctx, events); err != nil {
+			return fmt.Errorf("add events: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (u *Usecases) GetSlide(ctx context.Context, slideID uuid.UUID) (domain.Slide, error) {
+	slide, err := u.storage.GetSlide(ctx, slideID)
+	if err != nil {
+		return domain.Slide{}, fmt.Errorf("get slide: %w", err)
+	}
+
+	return slide, nil
+}
+
+func (u *Usecases) GetCaseSlides(ctx context.Context, caseID uuid.UUID) ([]domain.Slide, error) {
+	slides, err := u.storage.GetSlidesByCaseID(ctx, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("get slides by case id: %w", err)
+	}
+
+	return slides, nil
+}
+
+func (u *Usecases) GetSlideByBarcode(ctx context.Context, barcode string) (domain.Slide, error) {
+	slide, err := u.storage.GetSlideByBarcode(ctx, barcode)
+	if err != nil {
+		return domain.Slide{}, fmt.Errorf("get slide by barcode: %w", err)
+	}
+
+	return slide, nil
+}
+
+func (u *Usecases) StartSlide(ctx context.Context, slideID uuid.UUID) error {
+	return u.updateSlide(ctx, slideID, func(slide domain.Slide, caseSlides []domain.Slide) (domain.Slide, error) {
+		slide, err := u.service.StartSlide(slide, caseSlides)
+		if err != nil {
+			return domain.Slide{}, fmt.Errorf("start slide: %w", err)
+		}
+		return slide, nil
+	})
+}
+
+func (u *Usecases) FinishSlide(ctx context.Context, slideID uuid.UUID) error {
+	return u.updateSlide(ctx, slideID, func(slide domain.Slide, caseSlides []domain.Slide) (domain.Slide, error) {
+		slide, err := u.service.FinishSlide(slide, caseSlides)
+		if err != nil {
+			return domain.Slide{}, fmt.Errorf("finish slide: %w", err)
+		}
+		return slide, nil
+	})
+}
+
+func (u *Usecases) FailSlide(ctx context.Context, slideID uuid.UUID, reason string) error {
+	return u.updateSlide(ctx, slideID, func(slide domain.Slide, caseSlides []domain.Slide) (domain.Slide, error) {
+		slide, err := u.service.FailSlide(slide, caseSlides, reason)
+		if err != nil {
+			return domain.Slide{}, fmt.Errorf("fail slide: %w", err)
+		}
+		return slide, nil
+	})
+}
+
+// MoveSlide moves the slide into a block of another case. Both cases are
+// read and saved within the same transaction, so the event carries
+// consistent preparation statuses for each of them and a concurrent change
+// to either case fails with a version conflict.
+func (u *Usecases) MoveSlide(ctx context.Context, slideID, targetCaseID, targetBlockID uuid.UUID) error {
+	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		slide, err := u.storage.GetSlide(ctx, slideID)
+		if err != nil {
+			return fmt.Errorf("get slide: %w", err)
+		}
+
+		source, err := u.storage.GetCase(ctx, slide.CaseID)
+		if err != nil {
+			return fmt.Errorf("get source case: %w", err)
+		}
+
+		sourceSlides, err := u.storage.GetSlidesByCaseID(ctx, source.ID)
+		if err != nil {
+			return fmt.Errorf("get source slides: %w", err)
+		}
+
+		target, err := u.storage.GetCase(ctx, targetCaseID)
+		if err != nil {
+			return fmt.Errorf("get target case: %w", err)
+		}
+
+		targetSlides, err := u.storage.GetSlidesByCaseID(ctx, target.ID)
+		if err != nil {
+			return fmt.Errorf("get target slides: %w", err)
+		}
+
+		slide, err = u.service.MoveSlide(slide, source, sourceSlides, target, targetSlides, targetBlockID)
+		if err != nil {
+			return fmt.Errorf("move slide: %w", err)
+		}
+
+		return u.saveSlideMoves(ctx, target, source, []domain.Slide{slide})
+	})
+}
+
+// DetectStuckSlides moves slides that exceeded their processing timeout to
+// the error status. Each slide is handled in its own transaction so a single
+// conflicting slide does not block the rest.
+func (u *Usecases) DetectStuckSlides(ctx context.Context, timeouts domain.SlideTimeouts) error {
+	now := u.env.Clock.Now()
+
+	slides, err := u.storage.GetProcessingSlidesStartedBefore(ctx, now.Add(-timeouts.Min()))
+	if err != nil {
+		return fmt.Errorf("get processing slides: %w", err)
+	}
+
+	var errs []error
+	for _, slide := range slides {
+		if !u.service.IsSlideStuck(slide, timeouts, now) {
+			continue
+		}
+		err := u.updateSlide(ctx, slide.ID, func(slide domain.Slide, caseSlides []domain.Slide) (domain.Slide, error) {
+			return u.service.TimeOutSlide(slide, caseSlides, timeouts)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("time out slide %s: %w", slide.ID, err))
+			continue
+		}
+		u.logger.WarnContext(ctx, "slide timed out", "slide_id", slide.ID, "case_id", slide.CaseID)
+	}
+
+	return errors.Join(errs...)
+}
+
+// updateSlide loads the slide together with the rest of its case, applies fn
+// and persists the slide with the events it produced in a single transaction.
+func (u *Usecases) updateSlide(
+	ctx context.Context,
+	slideID uuid.UUID,
+	fn func(slide domain.Slide, caseSlides []domain.Slide) (domain.Slide, error),
+) error {
+	ctx = logging.With(ctx, "slide_id", slideID)
+
+	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		slide, err := u.storage.GetSlide(ctx, slideID)
+		if err != nil {
+			return fmt.Errorf("get slide: %w", err)
+		}
+		ctx = logging.With(ctx, "case_id", slide.CaseID)
+
+		caseSlides, err := u.storage.GetSlidesByCaseID(ctx, slide.CaseID)
+		if err != nil {
+			return fmt.Errorf("get slides by id: %w", err)
+		}
+
+		slide, err = fn(slide, caseSlides)
+		if err != nil {
+			return err
+		}
+
+		events := slide.PullEvents()
+		if err := u.storage.AddEvent(ctx, events); err != nil {
+			return fmt.Errorf("add events: %w", err)
+		}
+
+		if err := u.storage.SaveSlide(ctx, slide); err != nil {
+			return fmt.Errorf("save slide: %w", err)
+		}
+
+		u.logger.DebugContext(ctx, "slide updated", "version", slide.Version, "events", len(events))
+		return nil
+	})
+}
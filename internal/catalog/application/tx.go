@@ -0,0 +1,66 @@
+package application
+
+import "errors"
+
+// ErrTxOptionsConflict is returned when a nested transaction asks for options
+// the enclosing one cannot provide, such as writes inside a read-only one.
+var ErrTxOptionsConflict = errors.New("transaction options conflict with the enclosing transaction")
+
+type IsolationLevel uint8
+
+const (
+	IsolationDefault IsolationLevel = iota
+	IsolationReadCommitted
+	IsolationRepeatableRead
+	IsolationSerializable
+)
+
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+}
+
+type TxOption func(*TxOptions)
+
+func WithIsolation(level IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.Isolation = level
+	}
+}
+
+func ReadOnly() TxOption {
+	return func(o *TxOptions) {
+		o.ReadOnly = true
+	}
+}
+
+func NewTxOptions(opts ...TxOption) TxOptions {
+	var o TxOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Nested returns ErrTxOptionsConflict unless a transaction with options o can
+// run inside one started with the outer options. A nested transaction keeps
+// the outer isolation level, so the outer one must be at least as strong, and
+// a read-only one never hosts writes.
+func (o TxOptions) Nested(outer TxOptions) error {
+	if outer.ReadOnly && !o.ReadOnly {
+		return ErrTxOptionsConflict
+	}
+	if outer.Isolation.strength() < o.Isolation.strength() {
+		return ErrTxOptionsConflict
+	}
+	return nil
+}
+
+// strength orders the levels by the anomalies they prevent. The default
+// level of the supported databases is read committed or stronger.
+func (l IsolationLevel) strength() IsolationLevel {
+	if l == IsolationDefault {
+		return IsolationReadCommitted
+	}
+	return l
+}
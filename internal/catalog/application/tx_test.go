@@ -0,0 +1,67 @@
+package application
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTxOptionsNested(t *testing.T) {
+	tests := []struct {
+		name    string
+		outer   []TxOption
+		inner   []TxOption
+		wantErr error
+	}{
+		{"default in default", nil, nil, nil},
+		{"default in repeatable read", []TxOption{WithIsolation(IsolationRepeatableRead)}, nil, nil},
+		{"read committed in default", nil, []TxOption{WithIsolation(IsolationReadCommitted)}, nil},
+		{
+			"repeatable read in default",
+			nil,
+			[]TxOption{WithIsolation(IsolationRepeatableRead)},
+			ErrTxOptionsConflict,
+		},
+		{
+			"repeatable read in serializable",
+			[]TxOption{WithIsolation(IsolationSerializable)},
+			[]TxOption{WithIsolation(IsolationRepeatableRead)},
+			nil,
+		},
+		{
+			"serializable in repeatable read",
+			[]TxOption{WithIsolation(IsolationRepeatableRead)},
+			[]TxOption{WithIsolation(IsolationSerializable)},
+			ErrTxOptionsConflict,
+		},
+		{"read-only in default", nil, []TxOption{ReadOnly()}, nil},
+		{
+			"read-only repeatable read in default",
+			nil,
+			[]TxOption{ReadOnly(), WithIsolation(IsolationRepeatableRead)},
+			ErrTxOptionsConflict,
+		},
+		{
+			"read-only repeatable read in repeatable read",
+			[]TxOption{WithIsolation(IsolationRepeatableRead)},
+			[]TxOption{ReadOnly(), WithIsolation(IsolationRepeatableRead)},
+			nil,
+		},
+		{
+			"read-only serializable in read-only repeatable read",
+			[]TxOption{ReadOnly(), WithIsolation(IsolationRepeatableRead)},
+			[]TxOption{ReadOnly(), WithIsolation(IsolationSerializable)},
+			ErrTxOptionsConflict,
+		},
+		{"write in read-only", []TxOption{ReadOnly()}, nil, ErrTxOptionsConflict},
+		{"read-only in read-only", []TxOption{ReadOnly()}, []TxOption{ReadOnly()}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewTxOptions(tt.inner...).Nested(NewTxOptions(tt.outer...))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Nested: err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,28 +3,44 @@ package application
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/logging"
 )
 
 type casesRepo interface {
 	GetCase(ctx context.Context, caseID uuid.UUID) (domain.Case, error)
 	SaveCase(ctx context.Context, c domain.Case) error
+	GetDeletedCase(ctx context.Context, caseID uuid.UUID) (domain.Case, error)
+	GetCaseIDsDeletedBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error)
+	DeleteCase(ctx context.Context, c domain.Case) error
 }
 
 type slidesRepo interface {
 	GetSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error)
+	GetSlideByBarcode(ctx context.Context, barcode string) (domain.Slide, error)
+	GetSlidesByBarcodes(ctx context.Context, barcodes []string) ([]domain.Slide, error)
 	SaveSlide(ctx context.Context, s domain.Slide) error
+	SaveSlides(ctx context.Context, slides []domain.Slide) error
 	GetSlidesByCaseID(ctx context.Context, caseID uuid.UUID) ([]domain.Slide, error)
+	GetProcessingSlidesStartedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error)
+	GetNextQueuedSlide(ctx context.Context) (domain.Slide, error)
+	GetSlidesWithLeaseExpiredBefore(ctx context.Context, before time.Time) ([]domain.Slide, error)
+	GetDeletedSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error)
+	GetSlidesDeletedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error)
+	DeleteSlide(ctx context.Context, s domain.Slide) error
 }
 
 type eventsStorage interface {
 	AddEvent(ctx context.Context, events []domain.Event) error
 	MarkEventPublished(ctx context.Context, events []domain.Event) error
 	FetchUnpublishedEvents(ctx context.Context, limit int) ([]domain.Event, error)
+	FetchEvents(ctx context.Context, after time.Time, afterID uuid.UUID, limit int) ([]domain.Event, error)
+	ArchiveEvents(ctx context.Context, before time.Time, limit int) (int, error)
+	PruneArchivedEvents(ctx context.Context, before time.Time) (int, error)
 }
 
 type storage interface {
@@ -33,14 +49,18 @@ type storage interface {
 
 	eventsStorage
 
-	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	WithTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error
+	// ActiveTx returns the options of the transaction carried by ctx, if any.
+	ActiveTx(ctx context.Context) (TxOptions, bool)
 }
 
-func NewUsecases(storage storage, service *domain.Service) *Usecases {
+func NewUsecases(storage storage, service *domain.Service, env domain.Env, logger *slog.Logger) *Usecases {
 	return &Usecases{
 		storage:         storage,
-		eventsProcessor: newEventsProcessor(storage),
+		eventsProcessor: newEventsProcessor(storage, env.Clock, logger),
 		service:         service,
+		env:             env,
+		logger:          logger,
 	}
 }
 
@@ -48,36 +68,136 @@ type Usecases struct {
 	storage         storage
 	eventsProcessor *eventsProcessor
 	service         *domain.Service
+	env             domain.Env
+	logger          *slog.Logger
 }
 
-func (u *Usecases) CreateCase(ctx context.Context) error {
-	c := domain.CreateCase()
-	if err := u.storage.SaveCase(ctx, c); err != nil {
-		return fmt.Errorf("save case: %w", err)
+func (u *Usecases) CreateCase(ctx context.Context, priority domain.CasePriority) (uuid.UUID, error) {
+	c, err := domain.CreateCase(u.env, priority)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("create case: %w", err)
 	}
 
-	return nil
+	err = u.storage.WithTx(ctx, func(ctx context.Context) error {
+		if err := u.storage.SaveCase(ctx, c); err != nil {
+			return fmt.Errorf("save case: %w", err)
+		}
+
+		if err := u.storage.AddEvent(ctx, c.PullEvents()); err != nil {
+			return fmt.Errorf("add events: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	u.logger.InfoContext(ctx, "case created", "case_id", c.ID)
+	return c.ID, nil
 }
 
-func (u *Usecases) AddSlide(ctx context.Context, caseID uuid.UUID) error {
+func (u *Usecases) AddSpecimen(ctx context.Context, caseID uuid.UUID, description string) (uuid.UUID, error) {
+	var specimen domain.Specimen
+	err := u.updateCase(ctx, caseID, func(c *domain.Case) error {
+		var err error
+		if specimen, err = c.AddSpecimen(u.env, description); err != nil {
+			return fmt.Errorf("add specimen: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return specimen.ID, nil
+}
+
+func (u *Usecases) AddBlock(ctx context.Context, caseID, specimenID uuid.UUID) (uuid.UUID, error) {
+	var block domain.Block
+	err := u.updateCase(ctx, caseID, func(c *domain.Case) error {
+		var err error
+		if block, err = c.AddBlock(u.env, specimenID); err != nil {
+			return fmt.Errorf("add block: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return block.ID, nil
+}
+
+func (u *Usecases) HoldCase(ctx context.Context, caseID uuid.UUID, reason string) error {
+	return u.updateCase(ctx, caseID, func(c *domain.Case) error {
+		if err := c.Hold(u.env, reason); err != nil {
+			return fmt.Errorf("hold case: %w", err)
+		}
+		return nil
+	})
+}
+
+func (u *Usecases) ReleaseCaseHold(ctx context.Context, caseID uuid.UUID) error {
+	return u.updateCase(ctx, caseID, func(c *domain.Case) error {
+		if err := c.ReleaseHold(u.env); err != nil {
+			return fmt.Errorf("release case hold: %w", err)
+		}
+		return nil
+	})
+}
+
+func (u *Usecases) CancelCase(ctx context.Context, caseID uuid.UUID, reason string) error {
+	return u.updateCase(ctx, caseID, func(c *domain.Case) error {
+		if err := c.Cancel(u.env, reason); err != nil {
+			return fmt.Errorf("cancel case: %w", err)
+		}
+		return nil
+	})
+}
+
+func (u *Usecases) ReopenCase(ctx context.Context, caseID uuid.UUID, reason string) error {
+	return u.updateCase(ctx, caseID, func(c *domain.Case) error {
+		if err := c.Reopen(u.env, reason); err != nil {
+			return fmt.Errorf("reopen case: %w", err)
+		}
+		return nil
+	})
+}
+
+func (u *Usecases) AssignCase(ctx context.Context, caseID uuid.UUID, pathologist string) error {
+	return u.updateCase(ctx, caseID, func(c *domain.Case) error {
+		if err := c.AssignPathologist(u.env, pathologist); err != nil {
+			return fmt.Errorf("assign pathologist: %w", err)
+		}
+		return nil
+	})
+}
+
+// SignOutCase signs the case out on behalf of userID, who has to be the
+// pathologist the case is assigned to.
+func (u *Usecases) SignOutCase(ctx context.Context, caseID uuid.UUID, userID string) error {
 	return u.storage.WithTx(ctx, func(ctx context.Context) error {
+		c, err := u.storage.GetCase(ctx, caseID)
+		if err != nil {
+			return fmt.Errorf("get case: %w", err)
+		}
+
 		caseSlides, err := u.storage.GetSlidesByCaseID(ctx, caseID)
 		if err != nil {
 			return fmt.Errorf("get slides by id: %w", err)
 		}
 
-		slide := u.service.CreateSlide(caseID, caseSlides)
-
-		_, err = u.storage.GetCase(ctx, caseID)
+		c, err = u.service.SignOutCase(c, caseSlides, userID)
 		if err != nil {
-			return fmt.Errorf("get case: %w", err)
+			return fmt.Errorf("sign out case: %w", err)
 		}
 
-		if err := u.storage.SaveSlide(ctx, slide); err != nil {
-			return fmt.Errorf("save slide: %w", err)
+		if err := u.storage.SaveCase(ctx, c); err != nil {
+			return fmt.Errorf("save case: %w", err)
 		}
 
-		if err := u.storage.AddEvent(ctx, slide.PullEvents()); err != nil {
+		if err := u.storage.AddEvent(ctx, c.PullEvents()); err != nil {
 			return fmt.Errorf("add events: %w", err)
 		}
 
@@ -85,28 +205,31 @@ func (u *Usecases) AddSlide(ctx context.Context, caseID uuid.UUID) error {
 	})
 }
 
-func (u *Usecases) FinishSlide(ctx context.Context, slideID uuid.UUID) error {
+// updateCase loads the case, applies fn and persists the case together with
+// the events it produced in a single transaction.
+func (u *Usecases) updateCase(ctx context.Context, caseID uuid.UUID, fn func(c *domain.Case) error) error {
+	ctx = logging.With(ctx, "case_id", caseID)
+
 	return u.storage.WithTx(ctx, func(ctx context.Context) error {
-		slide, err := u.storage.GetSlide(ctx, slideID)
+		c, err := u.storage.GetCase(ctx, caseID)
 		if err != nil {
-			return fmt.Errorf("get slide: %w", err)
+			return fmt.Errorf("get case: %w", err)
 		}
 
-		caseSlides, err := u.storage.GetSlidesByCaseID(ctx, slide.CaseID)
-		if err != nil {
-			return fmt.Errorf("get slides by id: %w", err)
+		if err := fn(&c); err != nil {
+			return err
 		}
 
-		slide = u.service.FinishSlide(slide, slide.CaseID, caseSlides)
-
-		if err := u.storage.AddEvent(ctx, slide.PullEvents()); err != nil {
-			return fmt.Errorf("add events: %w", err)
+		if err := u.storage.SaveCase(ctx, c); err != nil {
+			return fmt.Errorf("save case: %w", err)
 		}
 
-		if err := u.storage.SaveSlide(ctx, slide); err != nil {
-			return fmt.Errorf("save slide: %w", err)
+		events := c.PullEvents()
+		if err := u.storage.AddEvent(ctx, events); err != nil {
+			return fmt.Errorf("add events: %w", err)
 		}
 
+		u.logger.DebugContext(ctx, "case updated", "version", c.Version, "events", len(events))
 		return nil
 	})
 }
@@ -115,7 +238,56 @@ func (u *Usecases) RegisterEventHandler(t domain.EventType, h EventHandler) {
 	u.eventsProcessor.Register(t, h)
 }
 
+// ListEvents returns up to limit stored events, archived ones included,
+// created after the given time.
+func (u *Usecases) ListEvents(ctx context.Context, after time.Time, limit int) ([]domain.Event, error) {
+	events, err := u.storage.FetchEvents(ctx, after, uuid.Nil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetch events: %w", err)
+	}
+	return events, nil
+}
+
+func (u *Usecases) ListUnpublishedEvents(ctx context.Context, limit int) ([]domain.Event, error) {
+	events, err := u.storage.FetchUnpublishedEvents(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetch unpublished events: %w", err)
+	}
+	return events, nil
+}
+
+// ReplayEvents runs the registered handlers over the whole event history and
+// returns the number of replayed events. Handlers must be idempotent, since
+// the events processor may deliver the same events again.
+func (u *Usecases) ReplayEvents(ctx context.Context) (int, error) {
+	return u.eventsProcessor.replay(ctx)
+}
+
 func (u *Usecases) StartEventsProcessor(interval time.Duration) {
+	runEvery(interval, func(ctx context.Context) {
+		if err := u.eventsProcessor.Process(ctx); err != nil {
+			u.logger.ErrorContext(ctx, "event processing failed", "error", err)
+		}
+	})
+}
+
+func (u *Usecases) StartEventsPruner(interval time.Duration, retention EventsRetention) {
+	runEvery(interval, func(ctx context.Context) {
+		if err := u.eventsProcessor.prune(ctx, retention); err != nil {
+			u.logger.ErrorContext(ctx, "events pruning failed", "error", err)
+		}
+	})
+}
+
+func (u *Usecases) StartStuckSlideDetector(interval time.Duration, timeouts domain.SlideTimeouts) {
+	runEvery(interval, func(ctx context.Context) {
+		if err := u.DetectStuckSlides(ctx, timeouts); err != nil {
+			u.logger.ErrorContext(ctx, "stuck slide detection failed", "error", err)
+		}
+	})
+}
+
+func runEvery(interval time.Duration, fn func(ctx context.Context)) {
 	ctx := context.TODO()
 	go func() {
 		ticker := time.NewTicker(interval)
@@ -124,9 +296,7 @@ func (u *Usecases) StartEventsProcessor(interval time.Duration) {
 		for {
 			select {
 			case <-ticker.C:
-				if err := u.eventsProcessor.Process(ctx); err != nil {
-					log.Printf("event processing failed: %v", err)
-				}
+				fn(ctx)
 			case <-ctx.Done():
 				return
 			}
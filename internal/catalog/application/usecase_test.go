@@ -0,0 +1,321 @@
+package application_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain/domaintest"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/memory"
+)
+
+var t0 = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+// newUsecases returns use cases on an empty memory storage with a fake clock
+// at t0 and sequential IDs.
+func newUsecases(t *testing.T) (*application.Usecases, *domaintest.Clock) {
+	t.Helper()
+
+	env, clock := domaintest.NewEnv(t0)
+	u := application.NewUsecases(memory.NewStorage(), domain.NewService(env), env, slog.New(slog.DiscardHandler))
+	return u, clock
+}
+
+// createCase stores a case with one specimen and one block and returns the
+// case and block IDs.
+func createCase(t *testing.T, u *application.Usecases, priority domain.CasePriority) (uuid.UUID, uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+
+	caseID, err := u.CreateCase(ctx, priority)
+	if err != nil {
+		t.Fatalf("create case: %v", err)
+	}
+	specimenID, err := u.AddSpecimen(ctx, caseID, "skin")
+	if err != nil {
+		t.Fatalf("add specimen: %v", err)
+	}
+	blockID, err := u.AddBlock(ctx, caseID, specimenID)
+	if err != nil {
+		t.Fatalf("add block: %v", err)
+	}
+	return caseID, blockID
+}
+
+func heSlide(blockID uuid.UUID, barcode string) domain.SlideSpec {
+	return domain.SlideSpec{
+		Stain:   domain.Stain{Type: domain.StainTypeHE},
+		BlockID: blockID,
+		Level:   1,
+		Barcode: barcode,
+	}
+}
+
+// TestCaseLifecycleEvents pins the events the use cases store. The fake clock
+// moves a minute before every command and IDs are handed out in order.
+func TestCaseLifecycleEvents(t *testing.T) {
+	ctx := context.Background()
+	u, clock := newUsecases(t)
+
+	caseID, err := u.CreateCase(ctx, domain.CasePriorityUrgent)
+	if err != nil {
+		t.Fatalf("create case: %v", err)
+	}
+	clock.Advance(time.Minute)
+	specimenID, err := u.AddSpecimen(ctx, caseID, "skin")
+	if err != nil {
+		t.Fatalf("add specimen: %v", err)
+	}
+	clock.Advance(time.Minute)
+	blockID, err := u.AddBlock(ctx, caseID, specimenID)
+	if err != nil {
+		t.Fatalf("add block: %v", err)
+	}
+	clock.Advance(time.Minute)
+	if err := u.AddSlide(ctx, caseID, heSlide(blockID, "S-1")); err != nil {
+		t.Fatalf("add slide: %v", err)
+	}
+	clock.Advance(time.Minute)
+	if err := u.AssignCase(ctx, caseID, "dr-grey"); err != nil {
+		t.Fatalf("assign case: %v", err)
+	}
+	clock.Advance(time.Minute)
+	if err := u.HoldCase(ctx, caseID, "awaiting consent"); err != nil {
+		t.Fatalf("hold case: %v", err)
+	}
+
+	events, err := u.ListEvents(ctx, time.Time{}, 100)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	assertEvents(t, events,
+		domain.EventCaseCreated{
+			ID:           id(2),
+			CreationTime: t0,
+			CaseID:       id(1),
+			Priority:     domain.CasePriorityUrgent,
+		},
+		domain.EventSpecimenAdded{
+			ID:           id(4),
+			CreationTime: t0.Add(time.Minute),
+			CaseID:       id(1),
+			SpecimenID:   id(3),
+			Label:        "A",
+		},
+		domain.EventBlockAdded{
+			ID:           id(6),
+			CreationTime: t0.Add(2 * time.Minute),
+			CaseID:       id(1),
+			SpecimenID:   id(3),
+			BlockID:      id(5),
+			Label:        "A1",
+		},
+		domain.EventSlideCreated{
+			ID:                    id(8),
+			CreationTime:          t0.Add(3 * time.Minute),
+			SlideID:               id(7),
+			CaseID:                id(1),
+			CasePriority:          domain.CasePriorityUrgent,
+			Stain:                 domain.Stain{Type: domain.StainTypeHE},
+			CasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+		domain.EventCaseAssigned{
+			ID:           id(10),
+			CreationTime: t0.Add(4 * time.Minute),
+			CaseID:       id(1),
+			Pathologist:  "dr-grey",
+		},
+		domain.EventCaseHeld{
+			ID:           id(11),
+			CreationTime: t0.Add(5 * time.Minute),
+			CaseID:       id(1),
+			Reason:       "awaiting consent",
+		},
+	)
+}
+
+func TestClaimNextSlideAndReturnExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	u, clock := newUsecases(t)
+
+	routineID, routineBlockID := createCase(t, u, domain.CasePriorityRoutine)
+	if err := u.AddSlide(ctx, routineID, heSlide(routineBlockID, "S-1")); err != nil {
+		t.Fatalf("add slide: %v", err)
+	}
+	statID, statBlockID := createCase(t, u, domain.CasePriorityStat)
+	if err := u.AddSlide(ctx, statID, heSlide(statBlockID, "S-2")); err != nil {
+		t.Fatalf("add slide: %v", err)
+	}
+
+	claimed, err := u.ClaimNextSlide(ctx, "tech-1", time.Hour)
+	if err != nil {
+		t.Fatalf("claim next slide: %v", err)
+	}
+	if claimed.CaseID != statID || claimed.Barcode != "S-2" {
+		t.Errorf("claimed slide %s of case %s, want S-2 of the stat case %s", claimed.Barcode, claimed.CaseID, statID)
+	}
+	if !claimed.LeaseExpiresAt.Equal(t0.Add(time.Hour)) {
+		t.Errorf("lease expires at %v, want %v", claimed.LeaseExpiresAt, t0.Add(time.Hour))
+	}
+
+	if err := u.ReturnExpiredLeases(ctx); err != nil {
+		t.Fatalf("return expired leases: %v", err)
+	}
+	if slide, err := u.GetSlide(ctx, claimed.ID); err != nil || slide.PreparationStatus != domain.SlidePreparationStatusProcessing {
+		t.Fatalf("slide before the lease expired = %v, %v, want processing", slide.PreparationStatus, err)
+	}
+
+	clock.Advance(time.Hour + time.Second)
+	if err := u.ReturnExpiredLeases(ctx); err != nil {
+		t.Fatalf("return expired leases: %v", err)
+	}
+	slide, err := u.GetSlide(ctx, claimed.ID)
+	if err != nil {
+		t.Fatalf("get slide: %v", err)
+	}
+	if slide.PreparationStatus != domain.SlidePreparationStatusNotStarted || slide.AssignedTo != "" {
+		t.Errorf("slide after the lease expired = %v assigned to %q, want not started and unassigned", slide.PreparationStatus, slide.AssignedTo)
+	}
+
+	again, err := u.ClaimNextSlide(ctx, "tech-2", time.Hour)
+	if err != nil {
+		t.Fatalf("claim next slide: %v", err)
+	}
+	if again.ID != claimed.ID {
+		t.Errorf("claimed %s after the lease expired, want %s back first", again.Barcode, claimed.Barcode)
+	}
+}
+
+func TestGetCaseFollowsMerges(t *testing.T) {
+	ctx := context.Background()
+	u, _ := newUsecases(t)
+
+	firstID, firstBlockID := createCase(t, u, domain.CasePriorityRoutine)
+	if err := u.AddSlide(ctx, firstID, heSlide(firstBlockID, "S-1")); err != nil {
+		t.Fatalf("add slide: %v", err)
+	}
+	secondID, _ := createCase(t, u, domain.CasePriorityRoutine)
+	thirdID, _ := createCase(t, u, domain.CasePriorityRoutine)
+
+	if err := u.MergeCases(ctx, firstID, secondID); err != nil {
+		t.Fatalf("merge first into second: %v", err)
+	}
+	if err := u.MergeCases(ctx, secondID, thirdID); err != nil {
+		t.Fatalf("merge second into third: %v", err)
+	}
+
+	c, err := u.GetCase(ctx, firstID)
+	if err != nil {
+		t.Fatalf("get case: %v", err)
+	}
+	if c.ID != thirdID {
+		t.Errorf("first case resolves to %s, want %s", c.ID, thirdID)
+	}
+
+	slide, err := u.GetSlideByBarcode(ctx, "S-1")
+	if err != nil {
+		t.Fatalf("get slide by barcode: %v", err)
+	}
+	if slide.CaseID != thirdID {
+		t.Errorf("slide is in case %s, want %s", slide.CaseID, thirdID)
+	}
+}
+
+func TestGetCaseInsideWritingTx(t *testing.T) {
+	ctx := context.Background()
+	env, _ := domaintest.NewEnv(t0)
+	storage := memory.NewStorage()
+	u := application.NewUsecases(storage, domain.NewService(env), env, slog.New(slog.DiscardHandler))
+	caseID, _ := createCase(t, u, domain.CasePriorityRoutine)
+
+	// The default isolation is weaker than the snapshot GetCase asks for on
+	// its own, so it has to read within the enclosing transaction.
+	err := storage.WithTx(ctx, func(ctx context.Context) error {
+		c, err := u.GetCase(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if c.ID != caseID {
+			t.Errorf("got case %s, want %s", c.ID, caseID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("get case inside writing tx: %v", err)
+	}
+
+	err = storage.WithTx(ctx, func(ctx context.Context) error {
+		_, err := u.GetCase(ctx, caseID)
+		return err
+	}, application.ReadOnly())
+	if !errors.Is(err, application.ErrTxOptionsConflict) {
+		t.Errorf("get case inside read committed read-only tx: err = %v, want %v", err, application.ErrTxOptionsConflict)
+	}
+}
+
+func TestPurgeDeletedKeepsMergeTarget(t *testing.T) {
+	ctx := context.Background()
+	u, clock := newUsecases(t)
+	policy := domain.DeletionPolicy{RestoreGrace: time.Hour, Retention: 24 * time.Hour}
+
+	sourceID, _ := createCase(t, u, domain.CasePriorityRoutine)
+	targetID, _ := createCase(t, u, domain.CasePriorityRoutine)
+	otherID, _ := createCase(t, u, domain.CasePriorityRoutine)
+	if err := u.MergeCases(ctx, sourceID, targetID); err != nil {
+		t.Fatalf("merge cases: %v", err)
+	}
+	if err := u.DeleteCase(ctx, targetID, "duplicate accession"); err != nil {
+		t.Fatalf("delete target: %v", err)
+	}
+	if err := u.DeleteCase(ctx, otherID, "duplicate accession"); err != nil {
+		t.Fatalf("delete other: %v", err)
+	}
+
+	clock.Advance(policy.Retention + time.Second)
+	err := u.PurgeDeleted(ctx, policy)
+	if !errors.Is(err, domain.ErrCaseHasMergedCases) {
+		t.Fatalf("purge deleted: err = %v, want %v", err, domain.ErrCaseHasMergedCases)
+	}
+
+	if err := u.RestoreCase(ctx, otherID, domain.DeletionPolicy{RestoreGrace: 48 * time.Hour}); !errors.Is(err, domain.ErrCaseNotFound) {
+		t.Errorf("restore purged case: err = %v, want %v", err, domain.ErrCaseNotFound)
+	}
+	if err := u.RestoreCase(ctx, targetID, domain.DeletionPolicy{RestoreGrace: 48 * time.Hour}); err != nil {
+		t.Fatalf("restore kept merge target: %v", err)
+	}
+	c, err := u.GetCase(ctx, sourceID)
+	if err != nil {
+		t.Fatalf("get merged case: %v", err)
+	}
+	if c.ID != targetID {
+		t.Errorf("merged case resolves to %s, want %s", c.ID, targetID)
+	}
+}
+
+func assertEvents(t *testing.T, got []domain.Event, want ...domain.Event) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("got %d events, want %d:\ngot  %+v\nwant %+v", len(got), len(want), got, want)
+		return
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("event %d:\ngot  %T%+v\nwant %T%+v", i, got[i], got[i], want[i], want[i])
+		}
+	}
+}
+
+// id returns the nth ID handed out by the domaintest env.
+func id(n uint64) uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[8:], n)
+	return id
+}
@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+// ClaimNextSlide hands the technician the next slide to process and marks it
+// as processing for the duration of the lease. Returns domain.ErrNoQueuedSlides
+// when the queue is empty.
+func (u *Usecases) ClaimNextSlide(ctx context.Context, technician string, lease time.Duration) (domain.Slide, error) {
+	var claimed domain.Slide
+
+	err := u.storage.WithTx(ctx, func(ctx context.Context) error {
+		slide, err := u.storage.GetNextQueuedSlide(ctx)
+		if err != nil {
+			return fmt.Errorf("get next queued slide: %w", err)
+		}
+
+		caseSlides, err := u.storage.GetSlidesByCaseID(ctx, slide.CaseID)
+		if err != nil {
+			return fmt.Errorf("get slides by id: %w", err)
+		}
+
+		slide, err = u.service.ClaimSlide(slide, caseSlides, technician, lease)
+		if err != nil {
+			return fmt.Errorf("claim slide: %w", err)
+		}
+
+		if err := u.storage.AddEvent(ctx, slide.PullEvents()); err != nil {
+			return fmt.Errorf("add events: %w", err)
+		}
+
+		if err := u.storage.SaveSlide(ctx, slide); err != nil {
+			return fmt.Errorf("save slide: %w", err)
+		}
+
+		claimed = slide
+		return nil
+	})
+	if err != nil {
+		return domain.Slide{}, err
+	}
+
+	return claimed, nil
+}
+
+func (u *Usecases) RenewSlideLease(ctx context.Context, slideID uuid.UUID, technician string, lease time.Duration) error {
+	return u.updateSlide(ctx, slideID, func(slide domain.Slide, _ []domain.Slide) (domain.Slide, error) {
+		slide, err := u.service.RenewSlideLease(slide, technician, lease)
+		if err != nil {
+			return domain.Slide{}, fmt.Errorf("renew slide lease: %w", err)
+		}
+		return slide, nil
+	})
+}
+
+func (u *Usecases) ReleaseSlide(ctx context.Context, slideID uuid.UUID, technician string) error {
+	return u.updateSlide(ctx, slideID, func(slide domain.Slide, caseSlides []domain.Slide) (domain.Slide, error) {
+		slide, err := u.service.ReleaseSlide(slide, caseSlides, technician)
+		if err != nil {
+			return domain.Slide{}, fmt.Errorf("release slide: %w", err)
+		}
+		return slide, nil
+	})
+}
+
+// ReturnExpiredLeases puts slides whose lease ran out back into the queue.
+func (u *Usecases) ReturnExpiredLeases(ctx context.Context) error {
+	now := u.env.Clock.Now()
+
+	slides, err := u.storage.GetSlidesWithLeaseExpiredBefore(ctx, now)
+	if err != nil {
+		return fmt.Errorf("get slides with expired lease: %w", err)
+	}
+
+	var errs []error
+	for _, slide := range slides {
+		err := u.updateSlide(ctx, slide.ID, func(slide domain.Slide, caseSlides []domain.Slide) (domain.Slide, error) {
+			return u.service.ExpireSlideLease(slide, caseSlides, now)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("expire lease of slide %s: %w", slide.ID, err))
+			continue
+		}
+		u.logger.InfoContext(ctx, "slide lease expired", "slide_id", slide.ID, "case_id", slide.CaseID)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (u *Usecases) StartLeaseReaper(interval time.Duration) {
+	runEvery(interval, func(ctx context.Context) {
+		if err := u.ReturnExpiredLeases(ctx); err != nil {
+			u.logger.ErrorContext(ctx, "returning expired leases failed", "error", err)
+		}
+	})
+}
@@ -1,49 +1,279 @@
 package config
 
 import (
-	"log"
-	"os"
-
-	"github.com/joho/godotenv"
-	"github.com/wintermonth2298/library-ddd/internal/pkg/env"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 )
 
 type Config struct {
-	PSQL PSQL
+	Storage     Storage     `yaml:"storage"`
+	PSQL        PSQL        `yaml:"postgres"`
+	PSQLReplica PSQLReplica `yaml:"postgres_replica"`
+	SQLite      SQLite      `yaml:"sqlite"`
+	HTTP        HTTP        `yaml:"http"`
+	StuckSlides StuckSlides `yaml:"stuck_slides"`
+	WorkQueue   WorkQueue   `yaml:"work_queue"`
+	Deletion    Deletion    `yaml:"deletion"`
+	Outbox      Outbox      `yaml:"outbox"`
+	Migrations  Migrations  `yaml:"migrations"`
+	Log         Log         `yaml:"log"`
+}
+
+// Migrations controls whether the server applies pending migrations on start.
+type Migrations struct {
+	AutoMigrate bool `yaml:"auto"`
+}
+
+// Outbox configures the events processor and retention of published events.
+// DeleteAfter drops archived events for good; keep it zero if the read models
+// may ever be rebuilt, since a replay only sees the events that are left.
+type Outbox struct {
+	PollInterval  time.Duration `yaml:"poll_interval"`
+	ArchiveAfter  time.Duration `yaml:"archive_after"`
+	DeleteAfter   time.Duration `yaml:"delete_after"`
+	PruneInterval time.Duration `yaml:"prune_interval"`
+}
+
+// Deletion configures soft deletion: deleted slides and cases can be restored
+// within RestoreGrace and are purged once Retention has passed.
+type Deletion struct {
+	RestoreGrace  time.Duration `yaml:"restore_grace"`
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+type WorkQueue struct {
+	Lease          time.Duration `yaml:"lease"`
+	ReaperInterval time.Duration `yaml:"reaper_interval"`
+}
+
+const (
+	StorageDriverPostgres = "postgres"
+	StorageDriverSQLite   = "sqlite"
+)
+
+// Storage selects the database of the catalog. SQLite keeps the write model
+// only, the read models and the HTTP API need Postgres.
+type Storage struct {
+	Driver string `yaml:"driver"`
+}
+
+type SQLite struct {
+	Path string `yaml:"path"`
 }
 
+type HTTP struct {
+	Addr              string        `yaml:"addr"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+}
+
+// PSQL holds the connection settings of Postgres. Zero pool settings keep
+// the database/sql defaults.
 type PSQL struct {
-	Port     string
-	User     string
-	Password string
-	DB       string
-	Host     string
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	DB       string `yaml:"db"`
+	Host     string `yaml:"host"`
+
+	SSLMode         string `yaml:"sslmode"`
+	SSLRootCert     string `yaml:"sslrootcert"`
+	ApplicationName string `yaml:"application_name"`
+
+	MaxOpenConns     int           `yaml:"max_open_conns"`
+	MaxIdleConns     int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime  time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime  time.Duration `yaml:"conn_max_idle_time"`
+	StatementTimeout time.Duration `yaml:"statement_timeout"`
+
+	ConnectTimeout time.Duration `yaml:"connect_timeout"`
+	ConnectRetries int           `yaml:"connect_retries"`
+	ConnectBackoff time.Duration `yaml:"connect_backoff"`
+}
+
+// PSQLReplica is an optional read replica for the query side. Without a Host
+// every query goes to the primary; the other settings default to the primary
+// ones. Reads stay on the primary for ReadYourWrites after a write.
+type PSQLReplica struct {
+	PSQL           `yaml:",inline"`
+	ReadYourWrites time.Duration `yaml:"read_your_writes"`
 }
 
-func MustLoad() *Config {
-	if err := godotenv.Load(); err != nil {
-		log.Panicf("No .env file found (fallback to OS environment)")
+// StuckSlides configures the detector of slides stuck in processing. Stain
+// specific timeouts fall back to Timeout when not set.
+type StuckSlides struct {
+	CheckInterval  time.Duration `yaml:"check_interval"`
+	Timeout        time.Duration `yaml:"timeout"`
+	HETimeout      time.Duration `yaml:"timeout_he"`
+	IHCTimeout     time.Duration `yaml:"timeout_ihc"`
+	SpecialTimeout time.Duration `yaml:"timeout_special"`
+}
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+type Log struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+func Default() Config {
+	return Config{
+		Storage: Storage{Driver: StorageDriverPostgres},
+		PSQL: PSQL{
+			ApplicationName: "catalog",
+			ConnectTimeout:  5 * time.Second,
+			ConnectRetries:  5,
+			ConnectBackoff:  time.Second,
+		},
+		SQLite: SQLite{Path: "catalog.db"},
+		HTTP: HTTP{
+			Addr:              ":8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			ShutdownTimeout:   10 * time.Second,
+		},
+		StuckSlides: StuckSlides{
+			CheckInterval: time.Minute,
+			Timeout:       24 * time.Hour,
+		},
+		WorkQueue: WorkQueue{
+			Lease:          30 * time.Minute,
+			ReaperInterval: time.Minute,
+		},
+		Deletion: Deletion{
+			RestoreGrace:  7 * 24 * time.Hour,
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Outbox: Outbox{
+			PollInterval:  5 * time.Second,
+			ArchiveAfter:  7 * 24 * time.Hour,
+			PruneInterval: time.Hour,
+		},
+		Migrations: Migrations{AutoMigrate: true},
+		Log: Log{
+			Level:  "info",
+			Format: LogFormatText,
+		},
 	}
+}
 
-	requiredEnvVars := []string{
-		"POSTGRES_USER",
-		"POSTGRES_PASSWORD",
-		"POSTGRES_DB",
-		"POSTGRES_HOST",
-		"POSTGRES_PORT",
+// fillDefaults derives the settings that default to other settings.
+func (c *Config) fillDefaults() {
+	for _, t := range []*time.Duration{&c.StuckSlides.HETimeout, &c.StuckSlides.IHCTimeout, &c.StuckSlides.SpecialTimeout} {
+		if *t == 0 {
+			*t = c.StuckSlides.Timeout
+		}
 	}
 
-	if err := env.CheckEnvVars(requiredEnvVars); err != nil {
-		log.Panicf("check env vars: %v", err)
+	r, p := &c.PSQLReplica.PSQL, c.PSQL
+	fill(&r.Port, p.Port)
+	fill(&r.User, p.User)
+	fill(&r.Password, p.Password)
+	fill(&r.DB, p.DB)
+	fill(&r.SSLMode, p.SSLMode)
+	fill(&r.SSLRootCert, p.SSLRootCert)
+	fill(&r.ApplicationName, p.ApplicationName)
+	fill(&r.MaxOpenConns, p.MaxOpenConns)
+	fill(&r.MaxIdleConns, p.MaxIdleConns)
+	fill(&r.ConnMaxLifetime, p.ConnMaxLifetime)
+	fill(&r.ConnMaxIdleTime, p.ConnMaxIdleTime)
+	fill(&r.StatementTimeout, p.StatementTimeout)
+	fill(&r.ConnectTimeout, p.ConnectTimeout)
+	fill(&r.ConnectRetries, p.ConnectRetries)
+	fill(&r.ConnectBackoff, p.ConnectBackoff)
+}
+
+func fill[T comparable](v *T, fallback T) {
+	var zero T
+	if *v == zero {
+		*v = fallback
 	}
+}
 
-	return &Config{
-		PSQL: PSQL{
-			Port:     os.Getenv("POSTGRES_PORT"),
-			User:     os.Getenv("POSTGRES_USER"),
-			Password: os.Getenv("POSTGRES_PASSWORD"),
-			DB:       os.Getenv("POSTGRES_DB"),
-			Host:     os.Getenv("POSTGRES_HOST"),
-		},
+// Validate reports every problem of the config at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
 	}
+	positive := func(d time.Duration, key string) {
+		check(d > 0, key, "must be positive, got %s", d)
+	}
+
+	check(slices.Contains([]string{StorageDriverPostgres, StorageDriverSQLite}, c.Storage.Driver),
+		"storage.driver", "unknown driver %q", c.Storage.Driver)
+
+	switch c.Storage.Driver {
+	case StorageDriverPostgres:
+		check(c.PSQL.Host != "", "postgres.host", "required")
+		check(c.PSQL.Port != "", "postgres.port", "required")
+		check(c.PSQL.User != "", "postgres.user", "required")
+		check(c.PSQL.Password != "", "postgres.password", "required")
+		check(c.PSQL.DB != "", "postgres.db", "required")
+		validatePSQL(check, "postgres", c.PSQL)
+		if c.PSQLReplica.Host != "" {
+			validatePSQL(check, "postgres_replica", c.PSQLReplica.PSQL)
+		}
+		check(c.PSQLReplica.ReadYourWrites >= 0, "postgres_replica.read_your_writes", "must not be negative")
+	case StorageDriverSQLite:
+		check(c.SQLite.Path != "", "sqlite.path", "required")
+	}
+
+	check(c.HTTP.Addr != "", "http.addr", "required")
+	positive(c.HTTP.ReadHeaderTimeout, "http.read_header_timeout")
+	positive(c.HTTP.ShutdownTimeout, "http.shutdown_timeout")
+
+	positive(c.StuckSlides.CheckInterval, "stuck_slides.check_interval")
+	positive(c.StuckSlides.Timeout, "stuck_slides.timeout")
+	positive(c.StuckSlides.HETimeout, "stuck_slides.timeout_he")
+	positive(c.StuckSlides.IHCTimeout, "stuck_slides.timeout_ihc")
+	positive(c.StuckSlides.SpecialTimeout, "stuck_slides.timeout_special")
+
+	positive(c.WorkQueue.Lease, "work_queue.lease")
+	positive(c.WorkQueue.ReaperInterval, "work_queue.reaper_interval")
+
+	positive(c.Deletion.RestoreGrace, "deletion.restore_grace")
+	positive(c.Deletion.PurgeInterval, "deletion.purge_interval")
+	check(c.Deletion.Retention >= c.Deletion.RestoreGrace, "deletion.retention",
+		"must not be shorter than deletion.restore_grace (%s)", c.Deletion.RestoreGrace)
+
+	positive(c.Outbox.PollInterval, "outbox.poll_interval")
+	positive(c.Outbox.PruneInterval, "outbox.prune_interval")
+	check(c.Outbox.ArchiveAfter >= 0, "outbox.archive_after", "must not be negative")
+	check(c.Outbox.DeleteAfter >= 0, "outbox.delete_after", "must not be negative")
+
+	check(slices.Contains([]string{"debug", "info", "warn", "error"}, c.Log.Level),
+		"log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	check(slices.Contains([]string{LogFormatText, LogFormatJSON}, c.Log.Format),
+		"log.format", "must be %s or %s, got %q", LogFormatText, LogFormatJSON, c.Log.Format)
+
+	return errors.Join(errs...)
+}
+
+// ValidateReadModels reports that the storage driver keeps no read models,
+// which the HTTP API and the projection rebuild need.
+func (c *Config) ValidateReadModels() error {
+	if c.Storage.Driver != StorageDriverPostgres {
+		return fmt.Errorf("storage.driver: %q keeps the write model only, the read models need %q",
+			c.Storage.Driver, StorageDriverPostgres)
+	}
+	return nil
+}
+
+var sslModes = []string{"", "disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+func validatePSQL(check func(ok bool, key, format string, args ...any), prefix string, c PSQL) {
+	check(slices.Contains(sslModes, c.SSLMode), prefix+".sslmode", "unknown mode %q", c.SSLMode)
+	check(c.MaxOpenConns >= 0, prefix+".max_open_conns", "must not be negative")
+	check(c.MaxIdleConns >= 0, prefix+".max_idle_conns", "must not be negative")
+	check(c.ConnectRetries >= 0, prefix+".connect_retries", "must not be negative")
+	check(c.StatementTimeout >= 0, prefix+".statement_timeout", "must not be negative")
 }
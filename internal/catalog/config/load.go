@@ -0,0 +1,232 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources are the inputs of Load besides the environment. File is a YAML
+// file; when empty, the CONFIG_FILE env var names it. Overrides are
+// "key=value" pairs with dotted keys such as "http.addr", as given on the
+// command line.
+type Sources struct {
+	File      string
+	Overrides []string
+}
+
+// envKeys maps env vars to config keys.
+var envKeys = map[string]string{
+	"STORAGE_DRIVER": "storage.driver",
+
+	"POSTGRES_HOST":               "postgres.host",
+	"POSTGRES_PORT":               "postgres.port",
+	"POSTGRES_USER":               "postgres.user",
+	"POSTGRES_PASSWORD":           "postgres.password",
+	"POSTGRES_DB":                 "postgres.db",
+	"POSTGRES_SSLMODE":            "postgres.sslmode",
+	"POSTGRES_SSLROOTCERT":        "postgres.sslrootcert",
+	"POSTGRES_APPLICATION_NAME":   "postgres.application_name",
+	"POSTGRES_MAX_OPEN_CONNS":     "postgres.max_open_conns",
+	"POSTGRES_MAX_IDLE_CONNS":     "postgres.max_idle_conns",
+	"POSTGRES_CONN_MAX_LIFETIME":  "postgres.conn_max_lifetime",
+	"POSTGRES_CONN_MAX_IDLE_TIME": "postgres.conn_max_idle_time",
+	"POSTGRES_STATEMENT_TIMEOUT":  "postgres.statement_timeout",
+	"POSTGRES_CONNECT_TIMEOUT":    "postgres.connect_timeout",
+	"POSTGRES_CONNECT_RETRIES":    "postgres.connect_retries",
+	"POSTGRES_CONNECT_BACKOFF":    "postgres.connect_backoff",
+
+	"POSTGRES_REPLICA_HOST":             "postgres_replica.host",
+	"POSTGRES_REPLICA_PORT":             "postgres_replica.port",
+	"POSTGRES_REPLICA_USER":             "postgres_replica.user",
+	"POSTGRES_REPLICA_PASSWORD":         "postgres_replica.password",
+	"POSTGRES_REPLICA_DB":               "postgres_replica.db",
+	"POSTGRES_REPLICA_READ_YOUR_WRITES": "postgres_replica.read_your_writes",
+
+	"SQLITE_PATH": "sqlite.path",
+
+	"HTTP_ADDR":                "http.addr",
+	"HTTP_READ_HEADER_TIMEOUT": "http.read_header_timeout",
+	"HTTP_SHUTDOWN_TIMEOUT":    "http.shutdown_timeout",
+
+	"STUCK_SLIDES_CHECK_INTERVAL":  "stuck_slides.check_interval",
+	"STUCK_SLIDES_TIMEOUT":         "stuck_slides.timeout",
+	"STUCK_SLIDES_TIMEOUT_HE":      "stuck_slides.timeout_he",
+	"STUCK_SLIDES_TIMEOUT_IHC":     "stuck_slides.timeout_ihc",
+	"STUCK_SLIDES_TIMEOUT_SPECIAL": "stuck_slides.timeout_special",
+
+	"WORK_QUEUE_LEASE":           "work_queue.lease",
+	"WORK_QUEUE_REAPER_INTERVAL": "work_queue.reaper_interval",
+
+	"DELETION_RESTORE_GRACE":  "deletion.restore_grace",
+	"DELETION_RETENTION":      "deletion.retention",
+	"DELETION_PURGE_INTERVAL": "deletion.purge_interval",
+
+	"OUTBOX_POLL_INTERVAL":  "outbox.poll_interval",
+	"OUTBOX_ARCHIVE_AFTER":  "outbox.archive_after",
+	"OUTBOX_DELETE_AFTER":   "outbox.delete_after",
+	"OUTBOX_PRUNE_INTERVAL": "outbox.prune_interval",
+
+	"MIGRATIONS_AUTO": "migrations.auto",
+
+	"LOG_LEVEL":  "log.level",
+	"LOG_FORMAT": "log.format",
+}
+
+// Load builds the config from the defaults, the YAML file, the environment
+// and the overrides, each layer replacing what the previous one set. A .env
+// file in the working directory is added to the environment if present.
+func Load(src Sources) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("load .env: %w", err)
+	}
+
+	cfg := Default()
+
+	file := src.File
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+
+	var errs []error
+	if file != "" {
+		if err := loadFile(&cfg, file); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(envKeys)) {
+		if value := os.Getenv(name); value != "" {
+			if err := set(&cfg, envKeys[name], value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	}
+	for _, override := range src.Overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok {
+			errs = append(errs, fmt.Errorf("override %q: expected key=value", override))
+			continue
+		}
+		if err := set(&cfg, key, value); err != nil {
+			errs = append(errs, fmt.Errorf("override %s: %w", key, err))
+		}
+	}
+
+	cfg.fillDefaults()
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &cfg, nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decode config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// set assigns the raw value to the dotted key, which names a field by its
+// yaml tag. The value is parsed by the type of the field, so a string field
+// takes it verbatim and unknown keys are rejected.
+func set(cfg *Config, key, value string) error {
+	v := reflect.ValueOf(cfg).Elem()
+	for _, name := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("unknown key %q", key)
+		}
+		var ok bool
+		if v, ok = field(v, name); !ok {
+			return fmt.Errorf("unknown key %q", key)
+		}
+	}
+
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("key %q is not a single value", key)
+	}
+	return nil
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// field returns the field of the struct v with the given yaml tag name,
+// looking into inlined structs as well.
+func field(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := range t.NumField() {
+		tag, opts, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if opts == "inline" {
+			if f, ok := field(v.Field(i), name); ok {
+				return f, true
+			}
+			continue
+		}
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// WriteYAML writes the config as YAML with passwords masked.
+func (c Config) WriteYAML(w io.Writer) error {
+	redact(&c.PSQL.Password)
+	redact(&c.PSQLReplica.Password)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	return enc.Close()
+}
+
+func redact(secret *string) {
+	if *secret != "" {
+		*secret = "REDACTED"
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		key, value string
+		check      func(c Config) bool
+	}{
+		{"postgres.password", "null", func(c Config) bool { return c.PSQL.Password == "null" }},
+		{"postgres.password", "~", func(c Config) bool { return c.PSQL.Password == "~" }},
+		{"postgres.password", "yes", func(c Config) bool { return c.PSQL.Password == "yes" }},
+		{"postgres.port", "0x10", func(c Config) bool { return c.PSQL.Port == "0x10" }},
+		{"postgres.password", "a: b", func(c Config) bool { return c.PSQL.Password == "a: b" }},
+		{"postgres.max_open_conns", "15", func(c Config) bool { return c.PSQL.MaxOpenConns == 15 }},
+		{"postgres_replica.host", "replica", func(c Config) bool { return c.PSQLReplica.Host == "replica" }},
+		{"outbox.delete_after", "36h", func(c Config) bool { return c.Outbox.DeleteAfter == 36*time.Hour }},
+		{"migrations.auto", "false", func(c Config) bool { return !c.Migrations.AutoMigrate }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cfg := Default()
+			if err := set(&cfg, tt.key, tt.value); err != nil {
+				t.Fatalf("set: %v", err)
+			}
+			if !tt.check(cfg) {
+				t.Errorf("%s was not set to %q", tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestSetRejects(t *testing.T) {
+	tests := []struct{ key, value string }{
+		{"postgres.nope", "x"},
+		{"postgres.host.extra", "x"},
+		{"postgres", "x"},
+		{"postgres.max_open_conns", "0x10"},
+		{"migrations.auto", "yes"},
+		{"outbox.delete_after", "10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cfg := Default()
+			if err := set(&cfg, tt.key, tt.value); err == nil {
+				t.Errorf("set %s=%q: want an error", tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("POSTGRES_MAX_OPEN_CONNS", "many")
+
+	_, err := Load(Sources{File: "missing.yaml", Overrides: []string{"http.addr"}})
+	if err == nil {
+		t.Fatal("Load: want an error")
+	}
+	for _, want := range []string{"open config file", "POSTGRES_MAX_OPEN_CONNS", `override "http.addr"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load error %q does not mention %q", err, want)
+		}
+	}
+}
@@ -2,22 +2,341 @@ package domain
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-var ErrCaseNotFound = errors.New("case not found")
+var (
+	ErrCaseNotFound          = errors.New("case not found")
+	ErrInvalidCasePriority   = errors.New("invalid case priority")
+	ErrInvalidCaseTransition = errors.New("invalid case status transition")
+	ErrCaseClosed            = errors.New("case is signed out, cancelled or merged")
+	ErrCaseSlidesNotDone     = errors.New("not all case slides are done")
+	ErrReasonRequired        = errors.New("reason is required")
+	ErrPathologistRequired   = errors.New("pathologist is required")
+	ErrCaseAlreadyAssigned   = errors.New("case is already assigned to the pathologist")
+	ErrCaseNotAssignedToUser = errors.New("case is not assigned to the user")
+	ErrMergeIntoItself       = errors.New("case cannot be merged into itself")
+	ErrNoSlidesToSplit       = errors.New("no slides selected to split")
+	ErrCaseDeleted           = errors.New("case is deleted")
+	ErrCaseNotDeleted        = errors.New("case is not deleted")
+	ErrCaseHasMergedCases    = errors.New("other cases were merged into the case")
+)
 
 type Case struct {
-	ID      uuid.UUID
-	Version Version
+	ID        uuid.UUID
+	Version   Version
+	Priority  CasePriority
+	Status    CaseStatus
+	Specimens []Specimen
+
+	Pathologist string
+	Assignments []CaseAssignment
+
+	// MergedInto is the case this one was merged into. Lookups of a merged
+	// case are redirected there.
+	MergedInto uuid.UUID
+	DeletedAt  time.Time
+
+	events []Event
+}
+
+// CaseAssignment is an entry of the case assignment history. The last
+// assignment is the current one.
+type CaseAssignment struct {
+	ID          uuid.UUID
+	Pathologist string
+	AssignedAt  time.Time
+}
+
+func CreateCase(env Env, priority CasePriority) (Case, error) {
+	if err := priority.validate(); err != nil {
+		return Case{}, err
+	}
+
+	c := Case{
+		ID:       env.newID(),
+		Version:  0,
+		Priority: priority,
+		Status:   CaseStatusOpen,
+	}
+
+	c.addEvent(EventCaseCreated{
+		ID:           env.newID(),
+		CreationTime: env.now(),
+		CaseID:       c.ID,
+		Priority:     c.Priority,
+	})
+
+	return c, nil
 }
 
-func CreateCase() Case {
-	return Case{
-		ID:      uuid.New(),
-		Version: 0,
+// IsClosed reports whether the case no longer accepts new material.
+func (c *Case) IsClosed() bool {
+	switch c.Status {
+	case CaseStatusSignedOut, CaseStatusCancelled, CaseStatusMerged:
+		return true
 	}
+	return !c.DeletedAt.IsZero()
+}
+
+func (c *Case) Hold(env Env, reason string) error {
+	if c.Status != CaseStatusOpen {
+		return ErrInvalidCaseTransition
+	}
+
+	c.Status = CaseStatusOnHold
+	c.addEvent(EventCaseHeld{
+		ID:           env.newID(),
+		CreationTime: env.now(),
+		CaseID:       c.ID,
+		Reason:       reason,
+	})
+
+	return nil
+}
+
+func (c *Case) ReleaseHold(env Env) error {
+	if c.Status != CaseStatusOnHold {
+		return ErrInvalidCaseTransition
+	}
+
+	c.Status = CaseStatusOpen
+	c.addEvent(EventCaseHoldReleased{
+		ID:           env.newID(),
+		CreationTime: env.now(),
+		CaseID:       c.ID,
+	})
+
+	return nil
+}
+
+func (c *Case) Cancel(env Env, reason string) error {
+	if c.Status != CaseStatusOpen && c.Status != CaseStatusOnHold {
+		return ErrInvalidCaseTransition
+	}
+
+	c.Status = CaseStatusCancelled
+	c.addEvent(EventCaseCancelled{
+		ID:           env.newID(),
+		CreationTime: env.now(),
+		CaseID:       c.ID,
+		Reason:       reason,
+	})
+
+	return nil
+}
+
+func (c *Case) Reopen(env Env, reason string) error {
+	if c.Status != CaseStatusSignedOut && c.Status != CaseStatusCancelled {
+		return ErrInvalidCaseTransition
+	}
+	if reason == "" {
+		return ErrReasonRequired
+	}
+
+	c.Status = CaseStatusOpen
+	c.addEvent(EventCaseReopened{
+		ID:           env.newID(),
+		CreationTime: env.now(),
+		CaseID:       c.ID,
+		Reason:       reason,
+	})
+
+	return nil
+}
+
+// Delete hides the case with all its slides. It can be restored until the
+// grace period of the deletion policy runs out.
+func (c *Case) Delete(env Env, reason string) error {
+	if !c.DeletedAt.IsZero() {
+		return ErrCaseDeleted
+	}
+	if reason == "" {
+		return ErrReasonRequired
+	}
+
+	c.DeletedAt = env.now()
+	c.addEvent(EventCaseDeleted{
+		ID:           env.newID(),
+		CreationTime: c.DeletedAt,
+		CaseID:       c.ID,
+		Reason:       reason,
+	})
+
+	return nil
+}
+
+func (c *Case) Restore(env Env, policy DeletionPolicy) error {
+	now := env.now()
+
+	if c.DeletedAt.IsZero() {
+		return ErrCaseNotDeleted
+	}
+	if !policy.canRestore(c.DeletedAt, now) {
+		return ErrRestoreGraceExpired
+	}
+
+	c.DeletedAt = time.Time{}
+	c.addEvent(EventCaseRestored{
+		ID:           env.newID(),
+		CreationTime: now,
+		CaseID:       c.ID,
+	})
+
+	return nil
+}
+
+// Purge records the tombstone of a case that is about to be removed from
+// storage together with everything it contains.
+func (c *Case) Purge(env Env, policy DeletionPolicy) error {
+	now := env.now()
+
+	if c.DeletedAt.IsZero() {
+		return ErrCaseNotDeleted
+	}
+	if !policy.canPurge(c.DeletedAt, now) {
+		return ErrRetentionNotExpired
+	}
+
+	c.addEvent(EventCasePurged{
+		ID:           env.newID(),
+		CreationTime: now,
+		CaseID:       c.ID,
+		DeletedAt:    c.DeletedAt,
+	})
+
+	return nil
+}
+
+func (c *Case) AssignPathologist(env Env, pathologist string) error {
+	if pathologist == "" {
+		return ErrPathologistRequired
+	}
+	if c.IsClosed() {
+		return ErrCaseClosed
+	}
+	if c.Pathologist == pathologist {
+		return ErrCaseAlreadyAssigned
+	}
+
+	assignment := CaseAssignment{
+		ID:          env.newID(),
+		Pathologist: pathologist,
+		AssignedAt:  env.now(),
+	}
+
+	previous := c.Pathologist
+	c.Pathologist = pathologist
+	c.Assignments = append(c.Assignments, assignment)
+
+	c.addEvent(EventCaseAssigned{
+		ID:                  env.newID(),
+		CreationTime:        assignment.AssignedAt,
+		CaseID:              c.ID,
+		Pathologist:         pathologist,
+		PreviousPathologist: previous,
+	})
+
+	return nil
+}
+
+func (c *Case) AddSpecimen(env Env, description string) (Specimen, error) {
+	if c.IsClosed() {
+		return Specimen{}, ErrCaseClosed
+	}
+
+	specimen := Specimen{
+		ID:          env.newID(),
+		CaseID:      c.ID,
+		Label:       specimenLabel(len(c.Specimens)),
+		Description: description,
+	}
+	c.Specimens = append(c.Specimens, specimen)
+
+	c.addEvent(EventSpecimenAdded{
+		ID:           env.newID(),
+		CreationTime: env.now(),
+		CaseID:       c.ID,
+		SpecimenID:   specimen.ID,
+		Label:        specimen.Label,
+	})
+
+	return specimen, nil
+}
+
+func (c *Case) AddBlock(env Env, specimenID uuid.UUID) (Block, error) {
+	if c.IsClosed() {
+		return Block{}, ErrCaseClosed
+	}
+
+	for i := range c.Specimens {
+		specimen := &c.Specimens[i]
+		if specimen.ID != specimenID {
+			continue
+		}
+
+		block := Block{
+			ID:         env.newID(),
+			SpecimenID: specimen.ID,
+			Label:      blockLabel(specimen.Label, len(specimen.Blocks)),
+		}
+		specimen.Blocks = append(specimen.Blocks, block)
+
+		c.addEvent(EventBlockAdded{
+			ID:           env.newID(),
+			CreationTime: env.now(),
+			CaseID:       c.ID,
+			SpecimenID:   specimen.ID,
+			BlockID:      block.ID,
+			Label:        block.Label,
+		})
+
+		return block, nil
+	}
+
+	return Block{}, ErrSpecimenNotFound
+}
+
+// copySpecimens adds copies of the given specimens with their blocks to the
+// case and returns the copied block for every original block ID.
+func (c *Case) copySpecimens(env Env, specimens []Specimen) (map[uuid.UUID]Block, error) {
+	blocks := make(map[uuid.UUID]Block)
+	for _, specimen := range specimens {
+		copied, err := c.AddSpecimen(env, specimen.Description)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range specimen.Blocks {
+			blocks[block.ID], err = c.AddBlock(env, copied.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return blocks, nil
+}
+
+func (c *Case) Block(blockID uuid.UUID) (Block, error) {
+	for _, specimen := range c.Specimens {
+		for _, block := range specimen.Blocks {
+			if block.ID == blockID {
+				return block, nil
+			}
+		}
+	}
+	return Block{}, ErrBlockNotFound
+}
+
+func (c *Case) PullEvents() []Event {
+	out := c.events
+	c.events = nil
+	return out
+}
+
+func (c *Case) addEvent(event Event) {
+	c.events = append(c.events, event)
 }
 
 type CasePreparationStatus uint8
@@ -29,3 +348,31 @@ const (
 	CasePreparationStatusDone
 	CasePreparationStatusError
 )
+
+type CasePriority uint8
+
+const (
+	CasePriorityUnknown CasePriority = iota
+	CasePriorityRoutine
+	CasePriorityUrgent
+	CasePriorityStat
+)
+
+func (p CasePriority) validate() error {
+	switch p {
+	case CasePriorityRoutine, CasePriorityUrgent, CasePriorityStat:
+		return nil
+	}
+	return ErrInvalidCasePriority
+}
+
+type CaseStatus uint8
+
+const (
+	CaseStatusUnknown CaseStatus = iota
+	CaseStatusOpen
+	CaseStatusOnHold
+	CaseStatusSignedOut
+	CaseStatusCancelled
+	CaseStatusMerged
+)
@@ -0,0 +1,255 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain/domaintest"
+)
+
+// The tests below pin the events of the Case commands the same way the
+// Service tests do. The fixture case is 1 and the next free ID is 7.
+
+func TestCreateCase(t *testing.T) {
+	env, _ := domaintest.NewEnv(t0)
+
+	c, err := domain.CreateCase(env, domain.CasePriorityUrgent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, c.PullEvents(),
+		domain.EventCaseCreated{
+			ID:           id(2),
+			CreationTime: t0,
+			CaseID:       id(1),
+			Priority:     domain.CasePriorityUrgent,
+		},
+	)
+}
+
+func TestAddSpecimen(t *testing.T) {
+	f := newFixture(t, 0)
+
+	specimen, err := f.c.AddSpecimen(f.env, "lymph node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if specimen.ID != id(7) || specimen.Label != "B" {
+		t.Errorf("specimen %s labelled %q, want %s labelled %q", specimen.ID, specimen.Label, id(7), "B")
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventSpecimenAdded{
+			ID:           id(8),
+			CreationTime: t1,
+			CaseID:       id(1),
+			SpecimenID:   id(7),
+			Label:        "B",
+		},
+	)
+}
+
+func TestAddBlock(t *testing.T) {
+	f := newFixture(t, 0)
+
+	block, err := f.c.AddBlock(f.env, id(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.ID != id(7) || block.Label != "A2" {
+		t.Errorf("block %s labelled %q, want %s labelled %q", block.ID, block.Label, id(7), "A2")
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventBlockAdded{
+			ID:           id(8),
+			CreationTime: t1,
+			CaseID:       id(1),
+			SpecimenID:   id(3),
+			BlockID:      id(7),
+			Label:        "A2",
+		},
+	)
+}
+
+func TestHoldCase(t *testing.T) {
+	f := newFixture(t, 0)
+
+	if err := f.c.Hold(f.env, "awaiting consent"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCaseHeld{
+			ID:           id(7),
+			CreationTime: t1,
+			CaseID:       id(1),
+			Reason:       "awaiting consent",
+		},
+	)
+}
+
+func TestReleaseCaseHold(t *testing.T) {
+	f := newFixture(t, 0)
+	f.hold(t)
+	f.clock.Advance(time.Hour)
+
+	if err := f.c.ReleaseHold(f.env); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCaseHoldReleased{
+			ID:           id(8),
+			CreationTime: t1.Add(time.Hour),
+			CaseID:       id(1),
+		},
+	)
+}
+
+func TestCancelCase(t *testing.T) {
+	f := newFixture(t, 0)
+	f.hold(t)
+
+	if err := f.c.Cancel(f.env, "duplicate accession"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCaseCancelled{
+			ID:           id(8),
+			CreationTime: t1,
+			CaseID:       id(1),
+			Reason:       "duplicate accession",
+		},
+	)
+}
+
+func TestReopenCase(t *testing.T) {
+	f := newFixture(t, 0)
+	if err := f.c.Cancel(f.env, "duplicate accession"); err != nil {
+		t.Fatal(err)
+	}
+	f.c.PullEvents()
+	f.clock.Advance(time.Hour)
+
+	if err := f.c.Reopen(f.env, "cancelled by mistake"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCaseReopened{
+			ID:           id(8),
+			CreationTime: t1.Add(time.Hour),
+			CaseID:       id(1),
+			Reason:       "cancelled by mistake",
+		},
+	)
+}
+
+func TestDeleteCase(t *testing.T) {
+	f := newFixture(t, 0)
+
+	if err := f.c.Delete(f.env, "duplicate accession"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCaseDeleted{
+			ID:           id(7),
+			CreationTime: t1,
+			CaseID:       id(1),
+			Reason:       "duplicate accession",
+		},
+	)
+}
+
+func TestRestoreCase(t *testing.T) {
+	f := newFixture(t, 0)
+	f.deleteCase(t)
+	f.clock.Advance(time.Hour)
+
+	if err := f.c.Restore(f.env, domain.DeletionPolicy{RestoreGrace: 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCaseRestored{
+			ID:           id(8),
+			CreationTime: t1.Add(time.Hour),
+			CaseID:       id(1),
+		},
+	)
+}
+
+func TestPurgeCase(t *testing.T) {
+	f := newFixture(t, 0)
+	f.deleteCase(t)
+	f.clock.Advance(48 * time.Hour)
+
+	if err := f.c.Purge(f.env, domain.DeletionPolicy{Retention: 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCasePurged{
+			ID:           id(8),
+			CreationTime: t1.Add(48 * time.Hour),
+			CaseID:       id(1),
+			DeletedAt:    t1,
+		},
+	)
+}
+
+func TestAssignPathologist(t *testing.T) {
+	f := newFixture(t, 0)
+
+	// Every assignment takes two IDs: one for the history entry and one for
+	// the event.
+	if err := f.c.AssignPathologist(f.env, "dr-grey"); err != nil {
+		t.Fatal(err)
+	}
+	f.clock.Advance(time.Hour)
+	if err := f.c.AssignPathologist(f.env, "dr-house"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCaseAssigned{
+			ID:           id(8),
+			CreationTime: t1,
+			CaseID:       id(1),
+			Pathologist:  "dr-grey",
+		},
+		domain.EventCaseAssigned{
+			ID:                  id(10),
+			CreationTime:        t1.Add(time.Hour),
+			CaseID:              id(1),
+			Pathologist:         "dr-house",
+			PreviousPathologist: "dr-grey",
+		},
+	)
+}
+
+// hold puts the fixture case on hold, which takes one ID.
+func (f *fixture) hold(t *testing.T) {
+	t.Helper()
+
+	if err := f.c.Hold(f.env, "awaiting consent"); err != nil {
+		t.Fatal(err)
+	}
+	f.c.PullEvents()
+}
+
+// deleteCase deletes the fixture case at the current time, which takes one
+// ID.
+func (f *fixture) deleteCase(t *testing.T) {
+	t.Helper()
+
+	if err := f.c.Delete(f.env, "duplicate accession"); err != nil {
+		t.Fatal(err)
+	}
+	f.c.PullEvents()
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+type IDGenerator interface {
+	NewID() uuid.UUID
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+type RandomIDs struct{}
+
+func (RandomIDs) NewID() uuid.UUID {
+	return uuid.New()
+}
+
+// Env supplies the current time and new IDs to the domain, so commands can be
+// replayed with a fixed clock and predictable IDs.
+type Env struct {
+	Clock Clock
+	IDs   IDGenerator
+}
+
+func SystemEnv() Env {
+	return Env{Clock: SystemClock{}, IDs: RandomIDs{}}
+}
+
+func (e Env) now() time.Time {
+	return e.Clock.Now()
+}
+
+func (e Env) newID() uuid.UUID {
+	return e.IDs.NewID()
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRestoreGraceExpired = errors.New("restore grace period has expired")
+	ErrRetentionNotExpired = errors.New("retention period has not expired")
+)
+
+// DeletionPolicy controls how long soft-deleted slides and cases can be
+// restored and when they are purged for good.
+type DeletionPolicy struct {
+	RestoreGrace time.Duration
+	Retention    time.Duration
+}
+
+func (p DeletionPolicy) canRestore(deletedAt, now time.Time) bool {
+	return now.Sub(deletedAt) <= p.RestoreGrace
+}
+
+func (p DeletionPolicy) canPurge(deletedAt, now time.Time) bool {
+	return now.Sub(deletedAt) > p.Retention
+}
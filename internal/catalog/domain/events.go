@@ -12,6 +12,30 @@ const (
 	EventTypeUnknown EventType = iota
 	EventTypeSlideCreated
 	EventTypeSlideFinished
+	EventTypeSpecimenAdded
+	EventTypeBlockAdded
+	EventTypeSlideStarted
+	EventTypeSlideFailed
+	EventTypeSlideTimedOut
+	EventTypeCaseCreated
+	EventTypeCaseSignedOut
+	EventTypeCaseHeld
+	EventTypeCaseHoldReleased
+	EventTypeCaseCancelled
+	EventTypeCaseReopened
+	EventTypeSlideLeaseRenewed
+	EventTypeSlideReleased
+	EventTypeCaseAssigned
+	EventTypeSlideMoved
+	EventTypeCaseMerged
+	EventTypeCaseSplit
+	EventTypeSlideDeleted
+	EventTypeSlideRestored
+	EventTypeSlidePurged
+	EventTypeCaseDeleted
+	EventTypeCaseRestored
+	EventTypeCasePurged
+	EventTypeCasePreparationStatusChanged
 )
 
 type Event interface {
@@ -24,7 +48,10 @@ type Event interface {
 type EventSlideCreated struct {
 	ID                    uuid.UUID
 	CreationTime          time.Time
+	SlideID               uuid.UUID
 	CaseID                uuid.UUID
+	CasePriority          CasePriority
+	Stain                 Stain
 	CasePreparationStatus CasePreparationStatus
 }
 
@@ -47,6 +74,7 @@ func (e EventSlideCreated) EventType() EventType {
 type EvenSlideFinished struct {
 	ID                    uuid.UUID
 	CreationTime          time.Time
+	SlideID               uuid.UUID
 	CaseID                uuid.UUID
 	CasePreparationStatus CasePreparationStatus
 }
@@ -66,3 +94,594 @@ func (e EvenSlideFinished) Name() string {
 func (e EvenSlideFinished) EventType() EventType {
 	return EventTypeSlideFinished
 }
+
+type EventSlideStarted struct {
+	ID                    uuid.UUID
+	CreationTime          time.Time
+	SlideID               uuid.UUID
+	CaseID                uuid.UUID
+	AssignedTo            string
+	LeaseExpiresAt        time.Time
+	CasePreparationStatus CasePreparationStatus
+}
+
+func (e EventSlideStarted) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSlideStarted) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSlideStarted) Name() string {
+	return "event(slide started)"
+}
+
+func (e EventSlideStarted) EventType() EventType {
+	return EventTypeSlideStarted
+}
+
+type EventSlideFailed struct {
+	ID                    uuid.UUID
+	CreationTime          time.Time
+	SlideID               uuid.UUID
+	CaseID                uuid.UUID
+	Reason                string
+	CasePreparationStatus CasePreparationStatus
+}
+
+func (e EventSlideFailed) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSlideFailed) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSlideFailed) Name() string {
+	return "event(slide failed)"
+}
+
+func (e EventSlideFailed) EventType() EventType {
+	return EventTypeSlideFailed
+}
+
+type EventSpecimenAdded struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	SpecimenID   uuid.UUID
+	Label        string
+}
+
+func (e EventSpecimenAdded) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSpecimenAdded) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSpecimenAdded) Name() string {
+	return "event(specimen added)"
+}
+
+func (e EventSpecimenAdded) EventType() EventType {
+	return EventTypeSpecimenAdded
+}
+
+type EventBlockAdded struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	SpecimenID   uuid.UUID
+	BlockID      uuid.UUID
+	Label        string
+}
+
+func (e EventBlockAdded) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventBlockAdded) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventBlockAdded) Name() string {
+	return "event(block added)"
+}
+
+func (e EventBlockAdded) EventType() EventType {
+	return EventTypeBlockAdded
+}
+
+type EventSlideTimedOut struct {
+	ID                    uuid.UUID
+	CreationTime          time.Time
+	SlideID               uuid.UUID
+	CaseID                uuid.UUID
+	Timeout               time.Duration
+	Reason                string
+	CasePreparationStatus CasePreparationStatus
+}
+
+func (e EventSlideTimedOut) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSlideTimedOut) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSlideTimedOut) Name() string {
+	return "event(slide timed out)"
+}
+
+func (e EventSlideTimedOut) EventType() EventType {
+	return EventTypeSlideTimedOut
+}
+
+type EventCaseCreated struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	Priority     CasePriority
+}
+
+func (e EventCaseCreated) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseCreated) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseCreated) Name() string {
+	return "event(case created)"
+}
+
+func (e EventCaseCreated) EventType() EventType {
+	return EventTypeCaseCreated
+}
+
+type EventCaseSignedOut struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	Pathologist  string
+}
+
+func (e EventCaseSignedOut) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseSignedOut) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseSignedOut) Name() string {
+	return "event(case signed out)"
+}
+
+func (e EventCaseSignedOut) EventType() EventType {
+	return EventTypeCaseSignedOut
+}
+
+type EventCaseHeld struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	Reason       string
+}
+
+func (e EventCaseHeld) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseHeld) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseHeld) Name() string {
+	return "event(case held)"
+}
+
+func (e EventCaseHeld) EventType() EventType {
+	return EventTypeCaseHeld
+}
+
+type EventCaseHoldReleased struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+}
+
+func (e EventCaseHoldReleased) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseHoldReleased) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseHoldReleased) Name() string {
+	return "event(case hold released)"
+}
+
+func (e EventCaseHoldReleased) EventType() EventType {
+	return EventTypeCaseHoldReleased
+}
+
+type EventCaseCancelled struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	Reason       string
+}
+
+func (e EventCaseCancelled) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseCancelled) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseCancelled) Name() string {
+	return "event(case cancelled)"
+}
+
+func (e EventCaseCancelled) EventType() EventType {
+	return EventTypeCaseCancelled
+}
+
+type EventCaseReopened struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	Reason       string
+}
+
+func (e EventCaseReopened) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseReopened) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseReopened) Name() string {
+	return "event(case reopened)"
+}
+
+func (e EventCaseReopened) EventType() EventType {
+	return EventTypeCaseReopened
+}
+
+type EventSlideLeaseRenewed struct {
+	ID             uuid.UUID
+	CreationTime   time.Time
+	SlideID        uuid.UUID
+	CaseID         uuid.UUID
+	AssignedTo     string
+	LeaseExpiresAt time.Time
+}
+
+func (e EventSlideLeaseRenewed) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSlideLeaseRenewed) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSlideLeaseRenewed) Name() string {
+	return "event(slide lease renewed)"
+}
+
+func (e EventSlideLeaseRenewed) EventType() EventType {
+	return EventTypeSlideLeaseRenewed
+}
+
+// EventSlideReleased returns a claimed slide to the work queue. Expired is
+// set when the release was caused by the lease running out.
+type EventSlideReleased struct {
+	ID                    uuid.UUID
+	CreationTime          time.Time
+	SlideID               uuid.UUID
+	CaseID                uuid.UUID
+	AssignedTo            string
+	Expired               bool
+	CasePreparationStatus CasePreparationStatus
+}
+
+func (e EventSlideReleased) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSlideReleased) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSlideReleased) Name() string {
+	return "event(slide released)"
+}
+
+func (e EventSlideReleased) EventType() EventType {
+	return EventTypeSlideReleased
+}
+
+type EventCaseAssigned struct {
+	ID                  uuid.UUID
+	CreationTime        time.Time
+	CaseID              uuid.UUID
+	Pathologist         string
+	PreviousPathologist string
+}
+
+func (e EventCaseAssigned) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseAssigned) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseAssigned) Name() string {
+	return "event(case assigned)"
+}
+
+func (e EventCaseAssigned) EventType() EventType {
+	return EventTypeCaseAssigned
+}
+
+// EventSlideMoved is raised when a slide is moved to another case. It carries
+// the preparation statuses of both cases after the move.
+type EventSlideMoved struct {
+	ID                          uuid.UUID
+	CreationTime                time.Time
+	SlideID                     uuid.UUID
+	FromCaseID                  uuid.UUID
+	ToCaseID                    uuid.UUID
+	BlockID                     uuid.UUID
+	Label                       string
+	CasePriority                CasePriority
+	SlidePreparationStatus      SlidePreparationStatus
+	SourceCasePreparationStatus CasePreparationStatus
+	TargetCasePreparationStatus CasePreparationStatus
+}
+
+func (e EventSlideMoved) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSlideMoved) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSlideMoved) Name() string {
+	return "event(slide moved)"
+}
+
+func (e EventSlideMoved) EventType() EventType {
+	return EventTypeSlideMoved
+}
+
+type EventCaseMerged struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	TargetCaseID uuid.UUID
+}
+
+func (e EventCaseMerged) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseMerged) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseMerged) Name() string {
+	return "event(case merged)"
+}
+
+func (e EventCaseMerged) EventType() EventType {
+	return EventTypeCaseMerged
+}
+
+type EventCaseSplit struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	NewCaseID    uuid.UUID
+	SlideIDs     []uuid.UUID
+}
+
+func (e EventCaseSplit) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseSplit) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseSplit) Name() string {
+	return "event(case split)"
+}
+
+func (e EventCaseSplit) EventType() EventType {
+	return EventTypeCaseSplit
+}
+
+type EventSlideDeleted struct {
+	ID                    uuid.UUID
+	CreationTime          time.Time
+	SlideID               uuid.UUID
+	CaseID                uuid.UUID
+	Reason                string
+	CasePreparationStatus CasePreparationStatus
+}
+
+func (e EventSlideDeleted) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSlideDeleted) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSlideDeleted) Name() string {
+	return "event(slide deleted)"
+}
+
+func (e EventSlideDeleted) EventType() EventType {
+	return EventTypeSlideDeleted
+}
+
+type EventSlideRestored struct {
+	ID                     uuid.UUID
+	CreationTime           time.Time
+	SlideID                uuid.UUID
+	CaseID                 uuid.UUID
+	SlidePreparationStatus SlidePreparationStatus
+	CasePreparationStatus  CasePreparationStatus
+}
+
+func (e EventSlideRestored) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSlideRestored) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSlideRestored) Name() string {
+	return "event(slide restored)"
+}
+
+func (e EventSlideRestored) EventType() EventType {
+	return EventTypeSlideRestored
+}
+
+// EventSlidePurged is the tombstone left in the event log once a deleted
+// slide is removed from storage.
+type EventSlidePurged struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	SlideID      uuid.UUID
+	CaseID       uuid.UUID
+	Barcode      string
+	DeletedAt    time.Time
+}
+
+func (e EventSlidePurged) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventSlidePurged) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventSlidePurged) Name() string {
+	return "event(slide purged)"
+}
+
+func (e EventSlidePurged) EventType() EventType {
+	return EventTypeSlidePurged
+}
+
+type EventCaseDeleted struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	Reason       string
+}
+
+func (e EventCaseDeleted) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseDeleted) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseDeleted) Name() string {
+	return "event(case deleted)"
+}
+
+func (e EventCaseDeleted) EventType() EventType {
+	return EventTypeCaseDeleted
+}
+
+type EventCaseRestored struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+}
+
+func (e EventCaseRestored) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCaseRestored) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCaseRestored) Name() string {
+	return "event(case restored)"
+}
+
+func (e EventCaseRestored) EventType() EventType {
+	return EventTypeCaseRestored
+}
+
+// EventCasePurged is the tombstone left in the event log once a deleted
+// case is removed from storage together with its slides.
+type EventCasePurged struct {
+	ID           uuid.UUID
+	CreationTime time.Time
+	CaseID       uuid.UUID
+	DeletedAt    time.Time
+}
+
+func (e EventCasePurged) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCasePurged) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCasePurged) Name() string {
+	return "event(case purged)"
+}
+
+func (e EventCasePurged) EventType() EventType {
+	return EventTypeCasePurged
+}
+
+// EventCasePreparationStatusChanged is raised by bulk operations that change
+// many slides at once instead of a status per slide event.
+type EventCasePreparationStatusChanged struct {
+	ID                    uuid.UUID
+	CreationTime          time.Time
+	CaseID                uuid.UUID
+	CasePreparationStatus CasePreparationStatus
+}
+
+func (e EventCasePreparationStatusChanged) EventID() uuid.UUID {
+	return e.ID
+}
+
+func (e EventCasePreparationStatusChanged) CreatedAt() time.Time {
+	return e.CreationTime
+}
+
+func (e EventCasePreparationStatusChanged) Name() string {
+	return "event(case preparation status changed)"
+}
+
+func (e EventCasePreparationStatusChanged) EventType() EventType {
+	return EventTypeCasePreparationStatusChanged
+}
@@ -1,58 +1,547 @@
 package domain
 
 import (
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type Service struct{}
+type Service struct {
+	env Env
+}
+
+func NewService(env Env) *Service {
+	return &Service{env: env}
+}
+
+func (s *Service) CreateSlide(c Case, caseSlides []Slide, spec SlideSpec) (Slide, error) {
+	if c.IsClosed() {
+		return Slide{}, ErrCaseClosed
+	}
 
-func NewService() *Service {
-	return &Service{}
+	slide, err := s.newSlide(c, caseSlides, spec)
+	if err != nil {
+		return Slide{}, err
+	}
+
+	slide.record(EventSlideCreated{
+		ID:                    s.env.newID(),
+		CreationTime:          s.env.now(),
+		SlideID:               slide.ID,
+		CaseID:                c.ID,
+		CasePriority:          c.Priority,
+		Stain:                 slide.Stain,
+		CasePreparationStatus: s.casePreparationStatus(withSlide(caseSlides, slide)),
+	})
+
+	return slide, nil
 }
 
-func (s *Service) CreateSlide(caseID uuid.UUID, caseSlides []Slide) Slide {
-	slideID := uuid.New()
+// CreateSlides creates a batch of slides for the case. Every slide event
+// carries the case preparation status after the whole batch, and a single
+// status change is recorded on the case if the batch changed it.
+func (s *Service) CreateSlides(c *Case, caseSlides []Slide, specs []SlideSpec) ([]Slide, error) {
+	if c.IsClosed() {
+		return nil, ErrCaseClosed
+	}
+	if len(specs) == 0 {
+		return nil, ErrNoSlideSpecs
+	}
+
+	all := caseSlides
+	slides := make([]Slide, 0, len(specs))
+	for _, spec := range specs {
+		slide, err := s.newSlide(*c, all, spec)
+		if err != nil {
+			return nil, fmt.Errorf("slide %q: %w", spec.Barcode, err)
+		}
+		all = withSlide(all, slide)
+		slides = append(slides, slide)
+	}
+
+	before := s.casePreparationStatus(caseSlides)
+	after := s.casePreparationStatus(all)
+	now := s.env.now()
 
-	slide := Slide{
-		ID:                slideID,
-		CaseID:            caseID,
+	for i := range slides {
+		slides[i].record(EventSlideCreated{
+			ID:                    s.env.newID(),
+			CreationTime:          now,
+			SlideID:               slides[i].ID,
+			CaseID:                c.ID,
+			CasePriority:          c.Priority,
+			Stain:                 slides[i].Stain,
+			CasePreparationStatus: after,
+		})
+	}
+
+	if before != after {
+		c.addEvent(EventCasePreparationStatusChanged{
+			ID:                    s.env.newID(),
+			CreationTime:          now,
+			CaseID:                c.ID,
+			CasePreparationStatus: after,
+		})
+	}
+
+	return slides, nil
+}
+
+func (s *Service) newSlide(c Case, caseSlides []Slide, spec SlideSpec) (Slide, error) {
+	if err := spec.validate(); err != nil {
+		return Slide{}, err
+	}
+	block, err := c.Block(spec.BlockID)
+	if err != nil {
+		return Slide{}, err
+	}
+	for _, sl := range caseSlides {
+		if sl.Barcode == spec.Barcode {
+			return Slide{}, ErrSlideBarcodeTaken
+		}
+	}
+
+	return Slide{
+		ID:                s.env.newID(),
+		CaseID:            c.ID,
 		Version:           0,
 		PreparationStatus: SlidePreparationStatusNotStarted,
+		Stain:             spec.Stain,
+		BlockID:           block.ID,
+		Level:             spec.Level,
+		Barcode:           spec.Barcode,
+		Label:             slideLabel(block, caseSlides),
+	}, nil
+}
+
+func (s *Service) StartSlide(slide Slide, caseSlides []Slide) (Slide, error) {
+	if slide.PreparationStatus != SlidePreparationStatusNotStarted {
+		return Slide{}, ErrInvalidSlideTransition
+	}
+
+	slide.PreparationStatus = SlidePreparationStatusProcessing
+	slide.record(EventSlideStarted{
+		ID:                    s.env.newID(),
+		CreationTime:          s.env.now(),
+		SlideID:               slide.ID,
+		CaseID:                slide.CaseID,
+		CasePreparationStatus: s.casePreparationStatus(withSlide(caseSlides, slide)),
+	})
+
+	return slide, nil
+}
+
+// ClaimSlide assigns a queued slide to the technician and starts processing
+// it. The claim is valid until the lease expires unless renewed.
+func (s *Service) ClaimSlide(slide Slide, caseSlides []Slide, technician string, lease time.Duration) (Slide, error) {
+	if technician == "" {
+		return Slide{}, ErrTechnicianRequired
+	}
+	if lease <= 0 {
+		return Slide{}, ErrInvalidLease
+	}
+	if slide.PreparationStatus != SlidePreparationStatusNotStarted {
+		return Slide{}, ErrInvalidSlideTransition
+	}
+
+	now := s.env.now()
+
+	slide.PreparationStatus = SlidePreparationStatusProcessing
+	slide.record(EventSlideStarted{
+		ID:                    s.env.newID(),
+		CreationTime:          now,
+		SlideID:               slide.ID,
+		CaseID:                slide.CaseID,
+		AssignedTo:            technician,
+		LeaseExpiresAt:        now.Add(lease),
+		CasePreparationStatus: s.casePreparationStatus(withSlide(caseSlides, slide)),
+	})
+
+	return slide, nil
+}
+
+func (s *Service) RenewSlideLease(slide Slide, technician string, lease time.Duration) (Slide, error) {
+	if lease <= 0 {
+		return Slide{}, ErrInvalidLease
+	}
+	if slide.PreparationStatus != SlidePreparationStatusProcessing || slide.AssignedTo != technician {
+		return Slide{}, ErrSlideNotAssigned
+	}
+
+	now := s.env.now()
+
+	slide.record(EventSlideLeaseRenewed{
+		ID:             s.env.newID(),
+		CreationTime:   now,
+		SlideID:        slide.ID,
+		CaseID:         slide.CaseID,
+		AssignedTo:     technician,
+		LeaseExpiresAt: now.Add(lease),
+	})
+
+	return slide, nil
+}
+
+func (s *Service) ReleaseSlide(slide Slide, caseSlides []Slide, technician string) (Slide, error) {
+	if slide.PreparationStatus != SlidePreparationStatusProcessing || slide.AssignedTo != technician {
+		return Slide{}, ErrSlideNotAssigned
+	}
+
+	return s.releaseSlide(slide, caseSlides, false), nil
+}
+
+// ExpireSlideLease returns the slide to the queue once its lease ran out.
+func (s *Service) ExpireSlideLease(slide Slide, caseSlides []Slide, now time.Time) (Slide, error) {
+	if slide.PreparationStatus != SlidePreparationStatusProcessing || slide.LeaseExpiresAt.IsZero() {
+		return Slide{}, ErrInvalidSlideTransition
+	}
+	if now.Before(slide.LeaseExpiresAt) {
+		return Slide{}, ErrSlideLeaseActive
 	}
 
-	slides := append(caseSlides, slide)
-	slide.addEvent(EventSlideCreated{
-		ID:                    uuid.New(),
-		CreationTime:          time.Now(),
-		CaseID:                caseID,
-		CasePreparationStatus: s.casePreparationStatus(slides),
+	return s.releaseSlide(slide, caseSlides, true), nil
+}
+
+func (s *Service) releaseSlide(slide Slide, caseSlides []Slide, expired bool) Slide {
+	assignee := slide.AssignedTo
+
+	slide.PreparationStatus = SlidePreparationStatusNotStarted
+	slide.record(EventSlideReleased{
+		ID:                    s.env.newID(),
+		CreationTime:          s.env.now(),
+		SlideID:               slide.ID,
+		CaseID:                slide.CaseID,
+		AssignedTo:            assignee,
+		Expired:               expired,
+		CasePreparationStatus: s.casePreparationStatus(withSlide(caseSlides, slide)),
 	})
 
 	return slide
 }
 
-func (s *Service) FinishSlide(slide Slide, caseID uuid.UUID, caseSlides []Slide) Slide {
-	sl := Slide{
-		ID:                slide.ID,
-		CaseID:            slide.CaseID,
-		Version:           slide.Version,
-		PreparationStatus: SlidePreparationStatusDone,
+// MoveSlide moves a mislabelled slide into a block of the target case. The
+// slide keeps its preparation status and gets a new label in the target block.
+func (s *Service) MoveSlide(
+	slide Slide,
+	source Case,
+	sourceSlides []Slide,
+	target Case,
+	targetSlides []Slide,
+	blockID uuid.UUID,
+) (Slide, error) {
+	if source.ID == target.ID {
+		return Slide{}, ErrSlideAlreadyInCase
 	}
+	if source.IsClosed() || target.IsClosed() {
+		return Slide{}, ErrCaseClosed
+	}
+	block, err := target.Block(blockID)
+	if err != nil {
+		return Slide{}, err
+	}
+
+	return s.moveSlide(slide, sourceSlides, target, targetSlides, block), nil
+}
+
+// MergeCases moves everything from source into target: specimens and blocks
+// are copied into target under new labels and all slides follow them. The
+// source case is marked as merged.
+func (s *Service) MergeCases(source, target *Case, sourceSlides, targetSlides []Slide) ([]Slide, error) {
+	if source.ID == target.ID {
+		return nil, ErrMergeIntoItself
+	}
+	if source.IsClosed() || target.IsClosed() {
+		return nil, ErrCaseClosed
+	}
+
+	blocks, err := target.copySpecimens(s.env, source.Specimens)
+	if err != nil {
+		return nil, err
+	}
+
+	moved := s.moveSlides(sourceSlides, sourceSlides, *target, targetSlides, blocks)
 
-	slides := append(caseSlides, slide)
-	sl.addEvent(EvenSlideFinished{
-		ID:                    uuid.New(),
-		CreationTime:          time.Now(),
-		CaseID:                caseID,
-		CasePreparationStatus: s.casePreparationStatus(slides),
+	source.Status = CaseStatusMerged
+	source.MergedInto = target.ID
+	source.addEvent(EventCaseMerged{
+		ID:           s.env.newID(),
+		CreationTime: s.env.now(),
+		CaseID:       source.ID,
+		TargetCaseID: target.ID,
 	})
 
-	return sl
+	return moved, nil
+}
+
+// SplitCase moves the selected slides into a new case with the same priority.
+// Only the specimens and blocks those slides come from are copied over.
+func (s *Service) SplitCase(source *Case, sourceSlides []Slide, slideIDs []uuid.UUID) (Case, []Slide, error) {
+	if source.IsClosed() {
+		return Case{}, nil, ErrCaseClosed
+	}
+	if len(slideIDs) == 0 {
+		return Case{}, nil, ErrNoSlidesToSplit
+	}
+
+	selected := make([]Slide, 0, len(slideIDs))
+	blockIDs := make(map[uuid.UUID]bool)
+	for _, id := range slideIDs {
+		if slices.ContainsFunc(selected, func(sl Slide) bool { return sl.ID == id }) {
+			continue
+		}
+		i := slices.IndexFunc(sourceSlides, func(sl Slide) bool { return sl.ID == id })
+		if i < 0 {
+			return Case{}, nil, ErrSlideNotFound
+		}
+		selected = append(selected, sourceSlides[i])
+		blockIDs[sourceSlides[i].BlockID] = true
+	}
+
+	var specimens []Specimen
+	for _, specimen := range source.Specimens {
+		var blocks []Block
+		for _, block := range specimen.Blocks {
+			if blockIDs[block.ID] {
+				blocks = append(blocks, block)
+			}
+		}
+		if len(blocks) > 0 {
+			specimen.Blocks = blocks
+			specimens = append(specimens, specimen)
+		}
+	}
+
+	target, err := CreateCase(s.env, source.Priority)
+	if err != nil {
+		return Case{}, nil, err
+	}
+	blocks, err := target.copySpecimens(s.env, specimens)
+	if err != nil {
+		return Case{}, nil, err
+	}
+
+	moved := s.moveSlides(selected, sourceSlides, target, nil, blocks)
+
+	movedIDs := make([]uuid.UUID, 0, len(moved))
+	for _, slide := range moved {
+		movedIDs = append(movedIDs, slide.ID)
+	}
+
+	source.addEvent(EventCaseSplit{
+		ID:           s.env.newID(),
+		CreationTime: s.env.now(),
+		CaseID:       source.ID,
+		NewCaseID:    target.ID,
+		SlideIDs:     movedIDs,
+	})
+
+	return target, moved, nil
+}
+
+// moveSlides moves slides one by one into the blocks they map to, keeping
+// both slide lists up to date so every event has the statuses after its move.
+func (s *Service) moveSlides(
+	slides []Slide,
+	sourceSlides []Slide,
+	target Case,
+	targetSlides []Slide,
+	blocks map[uuid.UUID]Block,
+) []Slide {
+	moved := make([]Slide, 0, len(slides))
+	for _, slide := range slides {
+		slide = s.moveSlide(slide, sourceSlides, target, targetSlides, blocks[slide.BlockID])
+		sourceSlides = withoutSlide(sourceSlides, slide.ID)
+		targetSlides = withSlide(targetSlides, slide)
+		moved = append(moved, slide)
+	}
+	return moved
+}
+
+func (s *Service) moveSlide(slide Slide, sourceSlides []Slide, target Case, targetSlides []Slide, block Block) Slide {
+	moved := slide
+	moved.CaseID = target.ID
+
+	slide.record(EventSlideMoved{
+		ID:                          s.env.newID(),
+		CreationTime:                s.env.now(),
+		SlideID:                     slide.ID,
+		FromCaseID:                  slide.CaseID,
+		ToCaseID:                    target.ID,
+		BlockID:                     block.ID,
+		Label:                       slideLabel(block, targetSlides),
+		CasePriority:                target.Priority,
+		SlidePreparationStatus:      slide.PreparationStatus,
+		SourceCasePreparationStatus: s.casePreparationStatus(withoutSlide(sourceSlides, slide.ID)),
+		TargetCasePreparationStatus: s.casePreparationStatus(withSlide(targetSlides, moved)),
+	})
+
+	return slide
+}
+
+// DeleteSlide hides the slide from its case. The case preparation status is
+// computed without it from now on.
+func (s *Service) DeleteSlide(slide Slide, caseSlides []Slide, reason string) (Slide, error) {
+	if !slide.DeletedAt.IsZero() {
+		return Slide{}, ErrSlideDeleted
+	}
+	if reason == "" {
+		return Slide{}, ErrReasonRequired
+	}
+
+	slide.record(EventSlideDeleted{
+		ID:                    s.env.newID(),
+		CreationTime:          s.env.now(),
+		SlideID:               slide.ID,
+		CaseID:                slide.CaseID,
+		Reason:                reason,
+		CasePreparationStatus: s.casePreparationStatus(withoutSlide(caseSlides, slide.ID)),
+	})
+
+	return slide, nil
+}
+
+func (s *Service) RestoreSlide(slide Slide, caseSlides []Slide, policy DeletionPolicy) (Slide, error) {
+	now := s.env.now()
+
+	if slide.DeletedAt.IsZero() {
+		return Slide{}, ErrSlideNotDeleted
+	}
+	if !policy.canRestore(slide.DeletedAt, now) {
+		return Slide{}, ErrRestoreGraceExpired
+	}
+
+	slide.record(EventSlideRestored{
+		ID:                     s.env.newID(),
+		CreationTime:           now,
+		SlideID:                slide.ID,
+		CaseID:                 slide.CaseID,
+		SlidePreparationStatus: slide.PreparationStatus,
+		CasePreparationStatus:  s.casePreparationStatus(withSlide(caseSlides, slide)),
+	})
+
+	return slide, nil
+}
+
+// PurgeSlide records the tombstone of a slide that is about to be removed
+// from storage.
+func (s *Service) PurgeSlide(slide Slide, policy DeletionPolicy) (Slide, error) {
+	now := s.env.now()
+
+	if slide.DeletedAt.IsZero() {
+		return Slide{}, ErrSlideNotDeleted
+	}
+	if !policy.canPurge(slide.DeletedAt, now) {
+		return Slide{}, ErrRetentionNotExpired
+	}
+
+	slide.record(EventSlidePurged{
+		ID:           s.env.newID(),
+		CreationTime: now,
+		SlideID:      slide.ID,
+		CaseID:       slide.CaseID,
+		Barcode:      slide.Barcode,
+		DeletedAt:    slide.DeletedAt,
+	})
+
+	return slide, nil
+}
+
+func (s *Service) FinishSlide(slide Slide, caseSlides []Slide) (Slide, error) {
+	if slide.PreparationStatus != SlidePreparationStatusProcessing {
+		return Slide{}, ErrInvalidSlideTransition
+	}
+
+	slide.PreparationStatus = SlidePreparationStatusDone
+	slide.record(EvenSlideFinished{
+		ID:                    s.env.newID(),
+		CreationTime:          s.env.now(),
+		SlideID:               slide.ID,
+		CaseID:                slide.CaseID,
+		CasePreparationStatus: s.casePreparationStatus(withSlide(caseSlides, slide)),
+	})
+
+	return slide, nil
+}
+
+func (s *Service) FailSlide(slide Slide, caseSlides []Slide, reason string) (Slide, error) {
+	switch slide.PreparationStatus {
+	case SlidePreparationStatusNotStarted, SlidePreparationStatusProcessing:
+	default:
+		return Slide{}, ErrInvalidSlideTransition
+	}
+
+	slide.PreparationStatus = SlidePreparationStatusError
+	slide.record(EventSlideFailed{
+		ID:                    s.env.newID(),
+		CreationTime:          s.env.now(),
+		SlideID:               slide.ID,
+		CaseID:                slide.CaseID,
+		Reason:                reason,
+		CasePreparationStatus: s.casePreparationStatus(withSlide(caseSlides, slide)),
+	})
+
+	return slide, nil
+}
+
+func (s *Service) SignOutCase(c Case, caseSlides []Slide, userID string) (Case, error) {
+	if c.Status != CaseStatusOpen {
+		return Case{}, ErrInvalidCaseTransition
+	}
+	if c.Pathologist == "" || c.Pathologist != userID {
+		return Case{}, ErrCaseNotAssignedToUser
+	}
+	if len(caseSlides) == 0 || s.casePreparationStatus(caseSlides) != CasePreparationStatusDone {
+		return Case{}, ErrCaseSlidesNotDone
+	}
+
+	c.Status = CaseStatusSignedOut
+	c.addEvent(EventCaseSignedOut{
+		ID:           s.env.newID(),
+		CreationTime: s.env.now(),
+		CaseID:       c.ID,
+		Pathologist:  userID,
+	})
+
+	return c, nil
+}
+
+// IsSlideStuck reports whether the slide has been processing for longer than
+// the timeout configured for its stain.
+func (s *Service) IsSlideStuck(slide Slide, timeouts SlideTimeouts, now time.Time) bool {
+	if slide.PreparationStatus != SlidePreparationStatusProcessing || slide.StartedAt.IsZero() {
+		return false
+	}
+	return now.Sub(slide.StartedAt) > timeouts.For(slide.Stain.Type)
+}
+
+func (s *Service) TimeOutSlide(slide Slide, caseSlides []Slide, timeouts SlideTimeouts) (Slide, error) {
+	if !s.IsSlideStuck(slide, timeouts, s.env.now()) {
+		return Slide{}, ErrInvalidSlideTransition
+	}
+
+	timeout := timeouts.For(slide.Stain.Type)
+
+	slide.PreparationStatus = SlidePreparationStatusError
+	slide.record(EventSlideTimedOut{
+		ID:                    s.env.newID(),
+		CreationTime:          s.env.now(),
+		SlideID:               slide.ID,
+		CaseID:                slide.CaseID,
+		Timeout:               timeout,
+		Reason:                fmt.Sprintf("processing timed out after %s", timeout),
+		CasePreparationStatus: s.casePreparationStatus(withSlide(caseSlides, slide)),
+	})
+
+	return slide, nil
 }
 
 func (s *Service) casePreparationStatus(slides []Slide) CasePreparationStatus {
+	if len(slides) == 0 {
+		return CasePreparationStatusNotStarted
+	}
+
 	var (
 		anyProcessing = false
 		anyErrors     = false
@@ -0,0 +1,621 @@
+package domain_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain/domaintest"
+)
+
+// The tests below pin the complete list of events every Service command
+// records. IDs come from domaintest in order, so a fixture with a case, a
+// specimen and a block has used IDs 1 to 6, and every fixture slide takes two
+// more: one for the slide and one for its created event.
+
+var (
+	t0 = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	// t1 is when the commands under test run, a minute after the fixture
+	// was set up.
+	t1 = t0.Add(time.Minute)
+)
+
+const technician = "tech-1"
+
+type fixture struct {
+	env     domain.Env
+	clock   *domaintest.Clock
+	service *domain.Service
+
+	c      domain.Case
+	block  domain.Block
+	slides []domain.Slide
+}
+
+// newFixture sets up an open routine case 1 with specimen 3 and block 5 and
+// the given number of H&E slides, without their events, and moves the clock
+// to t1.
+func newFixture(t *testing.T, slides int) *fixture {
+	t.Helper()
+
+	env, clock := domaintest.NewEnv(t0)
+	f := &fixture{env: env, clock: clock, service: domain.NewService(env)}
+	f.c, f.block = f.newCase(t, domain.CasePriorityRoutine)
+
+	for i := range slides {
+		slide, err := f.service.CreateSlide(f.c, f.slides, domain.SlideSpec{
+			Stain:   domain.Stain{Type: domain.StainTypeHE},
+			BlockID: f.block.ID,
+			Level:   1,
+			Barcode: fmt.Sprintf("S-%d", i+1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		slide.PullEvents()
+		f.slides = append(f.slides, slide)
+	}
+
+	clock.Set(t1)
+	return f
+}
+
+// newCase creates a case with a "skin" specimen and one block, which takes
+// six IDs, and drops their events.
+func (f *fixture) newCase(t *testing.T, priority domain.CasePriority) (domain.Case, domain.Block) {
+	t.Helper()
+
+	c, err := domain.CreateCase(f.env, priority)
+	if err != nil {
+		t.Fatal(err)
+	}
+	specimen, err := c.AddSpecimen(f.env, "skin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := c.AddBlock(f.env, specimen.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.PullEvents()
+	return c, block
+}
+
+func TestCreateSlide(t *testing.T) {
+	f := newFixture(t, 0)
+
+	slide, err := f.service.CreateSlide(f.c, nil, domain.SlideSpec{
+		Stain:   domain.Stain{Type: domain.StainTypeIHC, Name: "CD3"},
+		BlockID: f.block.ID,
+		Level:   2,
+		Barcode: "S-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideCreated{
+			ID:                    id(8),
+			CreationTime:          t1,
+			SlideID:               id(7),
+			CaseID:                id(1),
+			CasePriority:          domain.CasePriorityRoutine,
+			Stain:                 domain.Stain{Type: domain.StainTypeIHC, Name: "CD3"},
+			CasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+	assertEvents(t, f.c.PullEvents())
+}
+
+func TestCreateSlides(t *testing.T) {
+	f := newFixture(t, 0)
+
+	slides, err := f.service.CreateSlides(&f.c, nil, []domain.SlideSpec{
+		{Stain: domain.Stain{Type: domain.StainTypeHE}, BlockID: f.block.ID, Level: 1, Barcode: "S-1"},
+		{Stain: domain.Stain{Type: domain.StainTypeSpecial, Name: "PAS"}, BlockID: f.block.ID, Level: 2, Barcode: "S-2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slides[0].PullEvents(),
+		domain.EventSlideCreated{
+			ID:                    id(9),
+			CreationTime:          t1,
+			SlideID:               id(7),
+			CaseID:                id(1),
+			CasePriority:          domain.CasePriorityRoutine,
+			Stain:                 domain.Stain{Type: domain.StainTypeHE},
+			CasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+	assertEvents(t, slides[1].PullEvents(),
+		domain.EventSlideCreated{
+			ID:                    id(10),
+			CreationTime:          t1,
+			SlideID:               id(8),
+			CaseID:                id(1),
+			CasePriority:          domain.CasePriorityRoutine,
+			Stain:                 domain.Stain{Type: domain.StainTypeSpecial, Name: "PAS"},
+			CasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCasePreparationStatusChanged{
+			ID:                    id(11),
+			CreationTime:          t1,
+			CaseID:                id(1),
+			CasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+}
+
+func TestStartSlide(t *testing.T) {
+	f := newFixture(t, 1)
+
+	slide, err := f.service.StartSlide(f.slides[0], f.slides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideStarted{
+			ID:                    id(9),
+			CreationTime:          t1,
+			SlideID:               id(7),
+			CaseID:                id(1),
+			CasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+}
+
+func TestClaimSlide(t *testing.T) {
+	f := newFixture(t, 1)
+
+	slide, err := f.service.ClaimSlide(f.slides[0], f.slides, technician, 30*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideStarted{
+			ID:                    id(9),
+			CreationTime:          t1,
+			SlideID:               id(7),
+			CaseID:                id(1),
+			AssignedTo:            technician,
+			LeaseExpiresAt:        t1.Add(30 * time.Minute),
+			CasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+}
+
+func TestRenewSlideLease(t *testing.T) {
+	f := newFixture(t, 1)
+	slide := f.claim(t, f.slides[0])
+	f.clock.Advance(10 * time.Minute)
+
+	slide, err := f.service.RenewSlideLease(slide, technician, 30*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t2 := t1.Add(10 * time.Minute)
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideLeaseRenewed{
+			ID:             id(10),
+			CreationTime:   t2,
+			SlideID:        id(7),
+			CaseID:         id(1),
+			AssignedTo:     technician,
+			LeaseExpiresAt: t2.Add(30 * time.Minute),
+		},
+	)
+}
+
+func TestReleaseSlide(t *testing.T) {
+	f := newFixture(t, 1)
+	slide := f.claim(t, f.slides[0])
+	f.clock.Advance(10 * time.Minute)
+
+	slide, err := f.service.ReleaseSlide(slide, []domain.Slide{slide}, technician)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideReleased{
+			ID:                    id(10),
+			CreationTime:          t1.Add(10 * time.Minute),
+			SlideID:               id(7),
+			CaseID:                id(1),
+			AssignedTo:            technician,
+			CasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+}
+
+func TestExpireSlideLease(t *testing.T) {
+	f := newFixture(t, 1)
+	slide := f.claim(t, f.slides[0])
+	f.clock.Advance(31 * time.Minute)
+
+	slide, err := f.service.ExpireSlideLease(slide, []domain.Slide{slide}, f.clock.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideReleased{
+			ID:                    id(10),
+			CreationTime:          t1.Add(31 * time.Minute),
+			SlideID:               id(7),
+			CaseID:                id(1),
+			AssignedTo:            technician,
+			Expired:               true,
+			CasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+}
+
+func TestFinishSlide(t *testing.T) {
+	f := newFixture(t, 1)
+	slide := f.start(t, f.slides[0])
+	f.clock.Advance(time.Hour)
+
+	slide, err := f.service.FinishSlide(slide, []domain.Slide{slide})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EvenSlideFinished{
+			ID:                    id(10),
+			CreationTime:          t1.Add(time.Hour),
+			SlideID:               id(7),
+			CaseID:                id(1),
+			CasePreparationStatus: domain.CasePreparationStatusDone,
+		},
+	)
+}
+
+func TestFailSlide(t *testing.T) {
+	f := newFixture(t, 1)
+
+	slide, err := f.service.FailSlide(f.slides[0], f.slides, "torn section")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideFailed{
+			ID:                    id(9),
+			CreationTime:          t1,
+			SlideID:               id(7),
+			CaseID:                id(1),
+			Reason:                "torn section",
+			CasePreparationStatus: domain.CasePreparationStatusError,
+		},
+	)
+}
+
+func TestTimeOutSlide(t *testing.T) {
+	f := newFixture(t, 1)
+	slide := f.start(t, f.slides[0])
+	f.clock.Advance(2 * time.Hour)
+
+	slide, err := f.service.TimeOutSlide(slide, []domain.Slide{slide}, domain.SlideTimeouts{Default: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideTimedOut{
+			ID:                    id(10),
+			CreationTime:          t1.Add(2 * time.Hour),
+			SlideID:               id(7),
+			CaseID:                id(1),
+			Timeout:               time.Hour,
+			Reason:                "processing timed out after 1h0m0s",
+			CasePreparationStatus: domain.CasePreparationStatusError,
+		},
+	)
+}
+
+func TestDeleteSlide(t *testing.T) {
+	f := newFixture(t, 2)
+	finished := f.finish(t, f.slides[1])
+
+	slide, err := f.service.DeleteSlide(f.slides[0], []domain.Slide{f.slides[0], finished}, "broken glass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideDeleted{
+			ID:                    id(13),
+			CreationTime:          t1,
+			SlideID:               id(7),
+			CaseID:                id(1),
+			Reason:                "broken glass",
+			CasePreparationStatus: domain.CasePreparationStatusDone,
+		},
+	)
+}
+
+func TestRestoreSlide(t *testing.T) {
+	f := newFixture(t, 1)
+	deleted := f.delete(t, f.slides[0])
+	f.clock.Advance(time.Hour)
+
+	slide, err := f.service.RestoreSlide(deleted, nil, domain.DeletionPolicy{RestoreGrace: 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideRestored{
+			ID:                     id(10),
+			CreationTime:           t1.Add(time.Hour),
+			SlideID:                id(7),
+			CaseID:                 id(1),
+			SlidePreparationStatus: domain.SlidePreparationStatusNotStarted,
+			CasePreparationStatus:  domain.CasePreparationStatusProcessing,
+		},
+	)
+}
+
+func TestPurgeSlide(t *testing.T) {
+	f := newFixture(t, 1)
+	deleted := f.delete(t, f.slides[0])
+	f.clock.Advance(48 * time.Hour)
+
+	slide, err := f.service.PurgeSlide(deleted, domain.DeletionPolicy{Retention: 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlidePurged{
+			ID:           id(10),
+			CreationTime: t1.Add(48 * time.Hour),
+			SlideID:      id(7),
+			CaseID:       id(1),
+			Barcode:      "S-1",
+			DeletedAt:    t1,
+		},
+	)
+}
+
+func TestMoveSlide(t *testing.T) {
+	f := newFixture(t, 1)
+	target, targetBlock := f.newCase(t, domain.CasePriorityUrgent)
+
+	slide, err := f.service.MoveSlide(f.slides[0], f.c, f.slides, target, nil, targetBlock.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, slide.PullEvents(),
+		domain.EventSlideMoved{
+			ID:                          id(15),
+			CreationTime:                t1,
+			SlideID:                     id(7),
+			FromCaseID:                  id(1),
+			ToCaseID:                    id(9),
+			BlockID:                     id(13),
+			Label:                       "A1-1",
+			CasePriority:                domain.CasePriorityUrgent,
+			SlidePreparationStatus:      domain.SlidePreparationStatusNotStarted,
+			SourceCasePreparationStatus: domain.CasePreparationStatusNotStarted,
+			TargetCasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+}
+
+func TestMergeCases(t *testing.T) {
+	f := newFixture(t, 1)
+	target, _ := f.newCase(t, domain.CasePriorityUrgent)
+
+	moved, err := f.service.MergeCases(&f.c, &target, f.slides, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, target.PullEvents(),
+		domain.EventSpecimenAdded{
+			ID:           id(16),
+			CreationTime: t1,
+			CaseID:       id(9),
+			SpecimenID:   id(15),
+			Label:        "B",
+		},
+		domain.EventBlockAdded{
+			ID:           id(18),
+			CreationTime: t1,
+			CaseID:       id(9),
+			SpecimenID:   id(15),
+			BlockID:      id(17),
+			Label:        "B1",
+		},
+	)
+	assertEvents(t, moved[0].PullEvents(),
+		domain.EventSlideMoved{
+			ID:                          id(19),
+			CreationTime:                t1,
+			SlideID:                     id(7),
+			FromCaseID:                  id(1),
+			ToCaseID:                    id(9),
+			BlockID:                     id(17),
+			Label:                       "B1-1",
+			CasePriority:                domain.CasePriorityUrgent,
+			SlidePreparationStatus:      domain.SlidePreparationStatusNotStarted,
+			SourceCasePreparationStatus: domain.CasePreparationStatusNotStarted,
+			TargetCasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCaseMerged{
+			ID:           id(20),
+			CreationTime: t1,
+			CaseID:       id(1),
+			TargetCaseID: id(9),
+		},
+	)
+}
+
+func TestSplitCase(t *testing.T) {
+	f := newFixture(t, 2)
+
+	// A repeated ID moves the slide once and is listed once.
+	target, moved, err := f.service.SplitCase(&f.c, f.slides, []uuid.UUID{id(9), id(9)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, target.PullEvents(),
+		domain.EventCaseCreated{
+			ID:           id(12),
+			CreationTime: t1,
+			CaseID:       id(11),
+			Priority:     domain.CasePriorityRoutine,
+		},
+		domain.EventSpecimenAdded{
+			ID:           id(14),
+			CreationTime: t1,
+			CaseID:       id(11),
+			SpecimenID:   id(13),
+			Label:        "A",
+		},
+		domain.EventBlockAdded{
+			ID:           id(16),
+			CreationTime: t1,
+			CaseID:       id(11),
+			SpecimenID:   id(13),
+			BlockID:      id(15),
+			Label:        "A1",
+		},
+	)
+	assertEvents(t, moved[0].PullEvents(),
+		domain.EventSlideMoved{
+			ID:                          id(17),
+			CreationTime:                t1,
+			SlideID:                     id(9),
+			FromCaseID:                  id(1),
+			ToCaseID:                    id(11),
+			BlockID:                     id(15),
+			Label:                       "A1-1",
+			CasePriority:                domain.CasePriorityRoutine,
+			SlidePreparationStatus:      domain.SlidePreparationStatusNotStarted,
+			SourceCasePreparationStatus: domain.CasePreparationStatusProcessing,
+			TargetCasePreparationStatus: domain.CasePreparationStatusProcessing,
+		},
+	)
+	assertEvents(t, f.c.PullEvents(),
+		domain.EventCaseSplit{
+			ID:           id(18),
+			CreationTime: t1,
+			CaseID:       id(1),
+			NewCaseID:    id(11),
+			SlideIDs:     []uuid.UUID{id(9)},
+		},
+	)
+}
+
+func TestSignOutCase(t *testing.T) {
+	f := newFixture(t, 1)
+	finished := f.finish(t, f.slides[0])
+	if err := f.c.AssignPathologist(f.env, "dr-grey"); err != nil {
+		t.Fatal(err)
+	}
+	f.c.PullEvents()
+	f.clock.Advance(time.Hour)
+
+	c, err := f.service.SignOutCase(f.c, []domain.Slide{finished}, "dr-grey")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEvents(t, c.PullEvents(),
+		domain.EventCaseSignedOut{
+			ID:           id(13),
+			CreationTime: t1.Add(time.Hour),
+			CaseID:       id(1),
+			Pathologist:  "dr-grey",
+		},
+	)
+}
+
+// claim claims the slide for technician for 30 minutes, which takes one ID.
+func (f *fixture) claim(t *testing.T, slide domain.Slide) domain.Slide {
+	t.Helper()
+
+	slide, err := f.service.ClaimSlide(slide, []domain.Slide{slide}, technician, 30*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slide.PullEvents()
+	return slide
+}
+
+// start starts the slide, which takes one ID.
+func (f *fixture) start(t *testing.T, slide domain.Slide) domain.Slide {
+	t.Helper()
+
+	slide, err := f.service.StartSlide(slide, []domain.Slide{slide})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slide.PullEvents()
+	return slide
+}
+
+// finish starts and finishes the slide, which takes two IDs.
+func (f *fixture) finish(t *testing.T, slide domain.Slide) domain.Slide {
+	t.Helper()
+
+	slide, err := f.service.FinishSlide(f.start(t, slide), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slide.PullEvents()
+	return slide
+}
+
+// delete deletes the slide, which takes one ID.
+func (f *fixture) delete(t *testing.T, slide domain.Slide) domain.Slide {
+	t.Helper()
+
+	slide, err := f.service.DeleteSlide(slide, nil, "broken glass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slide.PullEvents()
+	return slide
+}
+
+func assertEvents(t *testing.T, got []domain.Event, want ...domain.Event) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("got %d events, want %d:\ngot  %+v\nwant %+v", len(got), len(want), got, want)
+		return
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("event %d:\ngot  %T%+v\nwant %T%+v", i, got[i], got[i], want[i], want[i])
+		}
+	}
+}
+
+// id returns the nth ID handed out by the domaintest env.
+func id(n uint64) uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[8:], n)
+	return id
+}
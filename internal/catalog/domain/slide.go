@@ -2,21 +2,76 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-var ErrSlideNotFound = errors.New("slide not found")
+var (
+	ErrSlideNotFound       = errors.New("slide not found")
+	ErrSlideBarcodeTaken   = errors.New("slide barcode already taken")
+	ErrInvalidSlideBarcode = errors.New("invalid slide barcode")
+	ErrInvalidSlideBlock   = errors.New("invalid slide block")
+	ErrInvalidSlideLevel   = errors.New("invalid slide section level")
+	ErrNoSlideSpecs        = errors.New("no slides to create")
+
+	ErrInvalidSlideTransition = errors.New("invalid slide preparation status transition")
+	ErrNoQueuedSlides         = errors.New("no slides waiting in the queue")
+	ErrTechnicianRequired     = errors.New("technician is required")
+	ErrInvalidLease           = errors.New("lease duration must be positive")
+	ErrSlideNotAssigned       = errors.New("slide is not assigned to the technician")
+	ErrSlideLeaseActive       = errors.New("slide lease has not expired")
+	ErrSlideAlreadyInCase     = errors.New("slide already belongs to the case")
+	ErrSlideDeleted           = errors.New("slide is deleted")
+	ErrSlideNotDeleted        = errors.New("slide is not deleted")
+)
 
 type Slide struct {
 	ID                uuid.UUID
 	CaseID            uuid.UUID
 	Version           Version
 	PreparationStatus SlidePreparationStatus
+	Stain             Stain
+	BlockID           uuid.UUID
+	Level             int
+	Barcode           string
+	Label             string
+	CreatedAt         time.Time
+	StartedAt         time.Time
+	FinishedAt        time.Time
+	FailedAt          time.Time
+	FailureReason     string
+	AssignedTo        string
+	LeaseExpiresAt    time.Time
+	DeletedAt         time.Time
 
 	events []Event
 }
 
+type SlideSpec struct {
+	Stain   Stain
+	BlockID uuid.UUID
+	Level   int
+	Barcode string
+}
+
+func (s SlideSpec) validate() error {
+	if err := s.Stain.validate(); err != nil {
+		return err
+	}
+	if s.BlockID == uuid.Nil {
+		return ErrInvalidSlideBlock
+	}
+	if s.Level < 1 {
+		return ErrInvalidSlideLevel
+	}
+	if s.Barcode == "" {
+		return ErrInvalidSlideBarcode
+	}
+	return nil
+}
+
 func (s *Slide) PullEvents() []Event {
 	out := s.events
 	s.events = nil
@@ -27,6 +82,89 @@ func (s *Slide) addEvent(event Event) {
 	s.events = append(s.events, event)
 }
 
+// record applies the event to the slide and queues it for publishing, so
+// the slide timestamps always match the events it produced.
+func (s *Slide) record(event Event) {
+	s.apply(event)
+	s.addEvent(event)
+}
+
+func (s *Slide) apply(event Event) {
+	switch e := event.(type) {
+	case EventSlideCreated:
+		s.CreatedAt = e.CreationTime
+	case EventSlideStarted:
+		s.StartedAt = e.CreationTime
+		s.AssignedTo = e.AssignedTo
+		s.LeaseExpiresAt = e.LeaseExpiresAt
+	case EventSlideLeaseRenewed:
+		s.LeaseExpiresAt = e.LeaseExpiresAt
+	case EventSlideReleased:
+		s.StartedAt = time.Time{}
+		s.AssignedTo = ""
+		s.LeaseExpiresAt = time.Time{}
+	case EvenSlideFinished:
+		s.FinishedAt = e.CreationTime
+		s.LeaseExpiresAt = time.Time{}
+	case EventSlideFailed:
+		s.FailedAt = e.CreationTime
+		s.FailureReason = e.Reason
+		s.LeaseExpiresAt = time.Time{}
+	case EventSlideTimedOut:
+		s.FailedAt = e.CreationTime
+		s.FailureReason = e.Reason
+		s.LeaseExpiresAt = time.Time{}
+	case EventSlideDeleted:
+		s.DeletedAt = e.CreationTime
+		s.AssignedTo = ""
+		s.LeaseExpiresAt = time.Time{}
+	case EventSlideRestored:
+		s.DeletedAt = time.Time{}
+	case EventSlideMoved:
+		s.CaseID = e.ToCaseID
+		s.BlockID = e.BlockID
+		s.Label = e.Label
+	}
+}
+
+// withSlide returns a copy of caseSlides where the slide with the same ID is
+// replaced by slide, or slide is appended if the case does not have it yet.
+func withSlide(caseSlides []Slide, slide Slide) []Slide {
+	slides := make([]Slide, 0, len(caseSlides)+1)
+	found := false
+	for _, sl := range caseSlides {
+		if sl.ID == slide.ID {
+			sl = slide
+			found = true
+		}
+		slides = append(slides, sl)
+	}
+	if !found {
+		slides = append(slides, slide)
+	}
+	return slides
+}
+
+func withoutSlide(caseSlides []Slide, slideID uuid.UUID) []Slide {
+	slides := make([]Slide, 0, len(caseSlides))
+	for _, sl := range caseSlides {
+		if sl.ID != slideID {
+			slides = append(slides, sl)
+		}
+	}
+	return slides
+}
+
+func slideLabel(block Block, caseSlides []Slide) string {
+	seq := 1
+	for _, slide := range caseSlides {
+		if slide.BlockID == block.ID {
+			seq++
+		}
+	}
+	return fmt.Sprintf("%s-%d", block.Label, seq)
+}
+
 type SlidePreparationStatus uint8
 
 const (
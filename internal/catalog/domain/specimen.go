@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSpecimenNotFound = errors.New("specimen not found")
+	ErrBlockNotFound    = errors.New("block not found")
+)
+
+// Specimen is a part of the case aggregate received from grossing. Specimens
+// are labelled A, B, ..., Z, AA, AB, ... in the order they are added.
+type Specimen struct {
+	ID          uuid.UUID
+	CaseID      uuid.UUID
+	Label       string
+	Description string
+	Blocks      []Block
+}
+
+// Block is cut from a specimen and yields slides. Blocks are labelled with the
+// specimen label followed by their ordinal, e.g. A1, A2.
+type Block struct {
+	ID         uuid.UUID
+	SpecimenID uuid.UUID
+	Label      string
+}
+
+func specimenLabel(n int) string {
+	label := ""
+	for n++; n > 0; n = (n - 1) / 26 {
+		label = string(rune('A'+(n-1)%26)) + label
+	}
+	return label
+}
+
+func blockLabel(specimenLabel string, n int) string {
+	return fmt.Sprintf("%s%d", specimenLabel, n+1)
+}
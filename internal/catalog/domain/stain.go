@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+)
+
+var ErrInvalidSlideStain = errors.New("invalid slide stain")
+
+type StainType uint8
+
+const (
+	StainTypeUnknown StainType = iota
+	StainTypeHE
+	StainTypeIHC
+	StainTypeSpecial
+)
+
+// Stain describes how a slide is stained. Name holds the IHC marker or the
+// special stain name and is empty for H&E.
+type Stain struct {
+	Type StainType
+	Name string
+}
+
+func (s Stain) validate() error {
+	switch s.Type {
+	case StainTypeHE:
+		if s.Name != "" {
+			return ErrInvalidSlideStain
+		}
+	case StainTypeIHC, StainTypeSpecial:
+		if s.Name == "" {
+			return ErrInvalidSlideStain
+		}
+	default:
+		return ErrInvalidSlideStain
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+)
+
+// SlideTimeouts limits how long a slide may stay in processing before it is
+// considered stuck. ByStain overrides Default for particular stain types.
+type SlideTimeouts struct {
+	Default time.Duration
+	ByStain map[StainType]time.Duration
+}
+
+func (t SlideTimeouts) For(stainType StainType) time.Duration {
+	if timeout, ok := t.ByStain[stainType]; ok {
+		return timeout
+	}
+	return t.Default
+}
+
+// Min returns the shortest configured timeout.
+func (t SlideTimeouts) Min() time.Duration {
+	shortest := t.Default
+	for _, timeout := range t.ByStain {
+		if timeout < shortest {
+			shortest = timeout
+		}
+	}
+	return shortest
+}
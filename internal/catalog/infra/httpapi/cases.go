@@ -0,0 +1,145 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/logging"
+)
+
+type slideCountsResponse struct {
+	NotStarted int `json:"not_started"`
+	Processing int `json:"processing"`
+	Done       int `json:"done"`
+	Error      int `json:"error"`
+}
+
+type caseSummaryResponse struct {
+	ID                string              `json:"id"`
+	Status            string              `json:"status"`
+	PreparationStatus string              `json:"preparation_status"`
+	Priority          string              `json:"priority"`
+	SlideCounts       slideCountsResponse `json:"slide_counts"`
+	MergedInto        string              `json:"merged_into,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+}
+
+type casePageResponse struct {
+	Cases      []caseSummaryResponse `json:"cases"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+var caseSorts = map[application.CaseSort]string{
+	application.CaseSortCreatedAt: "created_at",
+	application.CaseSortUpdatedAt: "updated_at",
+	application.CaseSortPriority:  "priority",
+}
+
+func (h *Handler) listCases(w http.ResponseWriter, r *http.Request) {
+	query, err := parseCaseListQuery(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	page, err := h.queries.ListCases(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	resp := casePageResponse{
+		Cases:      make([]caseSummaryResponse, 0, len(page.Cases)),
+		NextCursor: page.NextCursor,
+	}
+	for _, c := range page.Cases {
+		resp.Cases = append(resp.Cases, toCaseSummaryResponse(c))
+	}
+
+	h.writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (h *Handler) getCase(w http.ResponseWriter, r *http.Request) {
+	caseID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, r, fmt.Errorf("%w: invalid case id", errBadRequest))
+		return
+	}
+	r = r.WithContext(logging.With(r.Context(), "case_id", caseID))
+
+	c, err := h.queries.GetCase(r.Context(), caseID)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, toCaseSummaryResponse(c))
+}
+
+func toCaseSummaryResponse(c application.CaseSummary) caseSummaryResponse {
+	resp := caseSummaryResponse{
+		ID:                c.ID.String(),
+		Status:            caseStatusNames[c.Status],
+		PreparationStatus: casePreparationStatusNames[c.PreparationStatus],
+		Priority:          casePriorityNames[c.Priority],
+		SlideCounts: slideCountsResponse{
+			NotStarted: c.SlideCounts.NotStarted,
+			Processing: c.SlideCounts.Processing,
+			Done:       c.SlideCounts.Done,
+			Error:      c.SlideCounts.Error,
+		},
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if c.MergedInto != uuid.Nil {
+		resp.MergedInto = c.MergedInto.String()
+	}
+	return resp
+}
+
+func parseCaseListQuery(r *http.Request) (application.CaseListQuery, error) {
+	values := r.URL.Query()
+
+	var (
+		query application.CaseListQuery
+		err   error
+	)
+
+	if query.Status, err = parseEnum(values, "status", caseStatusNames); err != nil {
+		return application.CaseListQuery{}, err
+	}
+	if query.PreparationStatus, err = parseEnum(values, "preparation_status", casePreparationStatusNames); err != nil {
+		return application.CaseListQuery{}, err
+	}
+	if query.Priority, err = parseEnum(values, "priority", casePriorityNames); err != nil {
+		return application.CaseListQuery{}, err
+	}
+	if query.CreatedFrom, err = parseTime(values, "created_from"); err != nil {
+		return application.CaseListQuery{}, err
+	}
+	if query.CreatedTo, err = parseTime(values, "created_to"); err != nil {
+		return application.CaseListQuery{}, err
+	}
+	if query.Sort, err = parseEnum(values, "sort", caseSorts); err != nil {
+		return application.CaseListQuery{}, err
+	}
+	if query.Limit, err = parseInt(values, "limit"); err != nil {
+		return application.CaseListQuery{}, err
+	}
+
+	switch order := values.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return application.CaseListQuery{}, fmt.Errorf("%w: unknown order %q", errBadRequest, order)
+	}
+
+	query.Cursor = values.Get("cursor")
+
+	return query, nil
+}
@@ -0,0 +1,116 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/logging"
+)
+
+const (
+	headerRequestID     = "X-Request-ID"
+	headerCorrelationID = "X-Correlation-ID"
+)
+
+type Handler struct {
+	queries *application.Queries
+	logger  *slog.Logger
+	mux     *http.ServeMux
+}
+
+func NewHandler(queries *application.Queries, logger *slog.Logger) *Handler {
+	h := &Handler{
+		queries: queries,
+		logger:  logger,
+		mux:     http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /cases", h.listCases)
+	h.mux.HandleFunc("GET /cases/{id}", h.getCase)
+	h.mux.HandleFunc("GET /stats/turnaround/cases", h.caseTurnaround)
+	h.mux.HandleFunc("GET /stats/turnaround/daily", h.dailyTurnaround)
+	h.mux.HandleFunc("GET /pathologists/{id}/worklist", h.worklist)
+
+	return h
+}
+
+// ServeHTTP tags the request with a request ID and a correlation ID, taken
+// from the request headers when the caller sent them, and logs it once
+// served. Both IDs are echoed in the response headers and attached to every
+// log record of the request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	requestID := r.Header.Get(headerRequestID)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	correlationID := r.Header.Get(headerCorrelationID)
+	if correlationID == "" {
+		correlationID = requestID
+	}
+	w.Header().Set(headerRequestID, requestID)
+	w.Header().Set(headerCorrelationID, correlationID)
+
+	ctx := logging.With(r.Context(), "request_id", requestID, "correlation_id", correlationID)
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	h.mux.ServeHTTP(rec, r.WithContext(ctx))
+
+	h.logger.InfoContext(ctx, "http request",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status", rec.status,
+		"duration", time.Since(start),
+	)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// errBadRequest marks errors caused by invalid request parameters.
+var errBadRequest = errors.New("bad request")
+
+func (h *Handler) writeJSON(w http.ResponseWriter, r *http.Request, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.WarnContext(r.Context(), "write response failed", "error", err)
+	}
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errBadRequest), errors.Is(err, application.ErrInvalidCursor):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrCaseNotFound):
+		status = http.StatusNotFound
+	}
+
+	if status == http.StatusInternalServerError {
+		h.logger.ErrorContext(r.Context(), "request failed", "error", err)
+	}
+	h.writeJSON(w, r, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,83 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+var (
+	caseStatusNames = map[domain.CaseStatus]string{
+		domain.CaseStatusOpen:      "open",
+		domain.CaseStatusOnHold:    "on_hold",
+		domain.CaseStatusSignedOut: "signed_out",
+		domain.CaseStatusCancelled: "cancelled",
+		domain.CaseStatusMerged:    "merged",
+	}
+	casePreparationStatusNames = map[domain.CasePreparationStatus]string{
+		domain.CasePreparationStatusNotStarted: "not_started",
+		domain.CasePreparationStatusProcessing: "processing",
+		domain.CasePreparationStatusDone:       "done",
+		domain.CasePreparationStatusError:      "error",
+	}
+	casePriorityNames = map[domain.CasePriority]string{
+		domain.CasePriorityRoutine: "routine",
+		domain.CasePriorityUrgent:  "urgent",
+		domain.CasePriorityStat:    "stat",
+	}
+	stainTypeNames = map[domain.StainType]string{
+		domain.StainTypeHE:      "he",
+		domain.StainTypeIHC:     "ihc",
+		domain.StainTypeSpecial: "special",
+	}
+)
+
+// parseEnum looks the query parameter up among names. A missing parameter
+// yields the zero value, which the queries treat as "any".
+func parseEnum[T comparable](values url.Values, key string, names map[T]string) (T, error) {
+	var zero T
+
+	raw := values.Get(key)
+	if raw == "" {
+		return zero, nil
+	}
+
+	for value, name := range names {
+		if name == raw {
+			return value, nil
+		}
+	}
+	return zero, fmt.Errorf("%w: unknown %s %q", errBadRequest, key, raw)
+}
+
+func parseTime(values url.Values, key string) (time.Time, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid %s %q", errBadRequest, key, raw)
+	}
+	return t, nil
+}
+
+func parseInt(values url.Values, key string) (int, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: invalid %s %q", errBadRequest, key, raw)
+	}
+	return n, nil
+}
@@ -0,0 +1,104 @@
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+)
+
+type turnaroundStatsResponse struct {
+	Count         int     `json:"count"`
+	MedianSeconds float64 `json:"median_seconds"`
+	P90Seconds    float64 `json:"p90_seconds"`
+}
+
+type caseTurnaroundResponse struct {
+	CaseID string `json:"case_id"`
+	turnaroundStatsResponse
+}
+
+type dailyTurnaroundResponse struct {
+	Day string `json:"day"`
+	turnaroundStatsResponse
+}
+
+func (h *Handler) caseTurnaround(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTurnaroundFilter(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	stats, err := h.queries.CaseTurnaround(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	resp := make([]caseTurnaroundResponse, 0, len(stats))
+	for _, s := range stats {
+		resp = append(resp, caseTurnaroundResponse{
+			CaseID:                  s.CaseID.String(),
+			turnaroundStatsResponse: toTurnaroundStatsResponse(s.TurnaroundStats),
+		})
+	}
+
+	h.writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (h *Handler) dailyTurnaround(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTurnaroundFilter(r)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	stats, err := h.queries.DailyTurnaround(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	resp := make([]dailyTurnaroundResponse, 0, len(stats))
+	for _, s := range stats {
+		resp = append(resp, dailyTurnaroundResponse{
+			Day:                     s.Day.Format(time.DateOnly),
+			turnaroundStatsResponse: toTurnaroundStatsResponse(s.TurnaroundStats),
+		})
+	}
+
+	h.writeJSON(w, r, http.StatusOK, resp)
+}
+
+func parseTurnaroundFilter(r *http.Request) (application.TurnaroundFilter, error) {
+	values := r.URL.Query()
+
+	var (
+		filter application.TurnaroundFilter
+		err    error
+	)
+
+	if filter.From, err = parseTime(values, "from"); err != nil {
+		return application.TurnaroundFilter{}, err
+	}
+	if filter.To, err = parseTime(values, "to"); err != nil {
+		return application.TurnaroundFilter{}, err
+	}
+	if filter.StainType, err = parseEnum(values, "stain", stainTypeNames); err != nil {
+		return application.TurnaroundFilter{}, err
+	}
+	if filter.Priority, err = parseEnum(values, "priority", casePriorityNames); err != nil {
+		return application.TurnaroundFilter{}, err
+	}
+
+	return filter, nil
+}
+
+func toTurnaroundStatsResponse(s application.TurnaroundStats) turnaroundStatsResponse {
+	return turnaroundStatsResponse{
+		Count:         s.Count,
+		MedianSeconds: s.Median.Seconds(),
+		P90Seconds:    s.P90.Seconds(),
+	}
+}
@@ -0,0 +1,33 @@
+package httpapi
+
+import (
+	"net/http"
+	"time"
+)
+
+type worklistItemResponse struct {
+	CaseID     string    `json:"case_id"`
+	Priority   string    `json:"priority"`
+	AssignedAt time.Time `json:"assigned_at"`
+	ReadyAt    time.Time `json:"ready_at"`
+}
+
+func (h *Handler) worklist(w http.ResponseWriter, r *http.Request) {
+	items, err := h.queries.Worklist(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	resp := make([]worklistItemResponse, 0, len(items))
+	for _, item := range items {
+		resp = append(resp, worklistItemResponse{
+			CaseID:     item.CaseID.String(),
+			Priority:   casePriorityNames[item.Priority],
+			AssignedAt: item.AssignedAt,
+			ReadyAt:    item.ReadyAt,
+		})
+	}
+
+	h.writeJSON(w, r, http.StatusOK, resp)
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+func (s *Storage) GetCase(ctx context.Context, caseID uuid.UUID) (domain.Case, error) {
+	return s.getCase(ctx, caseID, false)
+}
+
+func (s *Storage) GetDeletedCase(ctx context.Context, caseID uuid.UUID) (domain.Case, error) {
+	return s.getCase(ctx, caseID, true)
+}
+
+func (s *Storage) getCase(ctx context.Context, caseID uuid.UUID, deleted bool) (domain.Case, error) {
+	var c domain.Case
+	err := s.do(ctx, func(st *state) error {
+		stored, ok := st.cases[caseID]
+		if !ok || stored.DeletedAt.IsZero() == deleted {
+			return domain.ErrCaseNotFound
+		}
+		c = cloneCase(stored)
+		return nil
+	})
+	return c, err
+}
+
+func (s *Storage) GetCaseIDsDeletedBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	var cases []domain.Case
+	_ = s.do(ctx, func(st *state) error {
+		for _, c := range st.cases {
+			if !c.DeletedAt.IsZero() && c.DeletedAt.Before(before) {
+				cases = append(cases, c)
+			}
+		}
+		return nil
+	})
+
+	slices.SortFunc(cases, func(a, b domain.Case) int {
+		return a.DeletedAt.Compare(b.DeletedAt)
+	})
+
+	ids := make([]uuid.UUID, 0, len(cases))
+	for _, c := range cases {
+		ids = append(ids, c.ID)
+	}
+	return ids, nil
+}
+
+func (s *Storage) SaveCase(ctx context.Context, c domain.Case) error {
+	return s.do(ctx, func(st *state) error {
+		if c.MergedInto != uuid.Nil {
+			if _, ok := st.cases[c.MergedInto]; !ok {
+				return domain.ErrCaseNotFound
+			}
+		}
+
+		stored, ok := st.cases[c.ID]
+		if c.Version == 0 {
+			if ok {
+				return domain.ErrVersionConflict
+			}
+		} else if !ok || stored.Version != c.Version {
+			return domain.ErrVersionConflict
+		}
+
+		c = cloneCase(c)
+		c.Version++
+		st.cases[c.ID] = c
+		return nil
+	})
+}
+
+// DeleteCase removes the case for good together with its slides. A case that
+// others were merged into is kept.
+func (s *Storage) DeleteCase(ctx context.Context, c domain.Case) error {
+	return s.do(ctx, func(st *state) error {
+		stored, ok := st.cases[c.ID]
+		if !ok || stored.Version != c.Version {
+			return domain.ErrVersionConflict
+		}
+
+		for _, other := range st.cases {
+			if other.MergedInto == c.ID {
+				return domain.ErrCaseHasMergedCases
+			}
+		}
+
+		for id, slide := range st.slides {
+			if slide.CaseID == c.ID {
+				delete(st.slides, id)
+			}
+		}
+		delete(st.cases, c.ID)
+		return nil
+	})
+}
+
+// blockExists reports whether a block with the given ID belongs to any
+// stored case.
+func (st *state) blockExists(blockID uuid.UUID) bool {
+	for _, c := range st.cases {
+		for _, sp := range c.Specimens {
+			for _, b := range sp.Blocks {
+				if b.ID == blockID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func cloneCase(c domain.Case) domain.Case {
+	c.PullEvents()
+
+	specimens := make([]domain.Specimen, 0, len(c.Specimens))
+	for _, sp := range c.Specimens {
+		sp.Blocks = slices.Clone(sp.Blocks)
+		specimens = append(specimens, sp)
+	}
+	c.Specimens = specimens
+	c.Assignments = slices.Clone(c.Assignments)
+
+	return c
+}
+
+func compareIDs(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
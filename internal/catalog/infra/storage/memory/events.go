@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+type storedEvent struct {
+	event     domain.Event
+	published bool
+}
+
+func (s *Storage) AddEvent(ctx context.Context, events []domain.Event) error {
+	return s.do(ctx, func(st *state) error {
+		for _, e := range events {
+			st.events = append(st.events, storedEvent{event: e})
+		}
+		return nil
+	})
+}
+
+func (s *Storage) MarkEventPublished(ctx context.Context, events []domain.Event) error {
+	return s.do(ctx, func(st *state) error {
+		for i, stored := range st.events {
+			if slices.ContainsFunc(events, func(e domain.Event) bool {
+				return e.EventID() == stored.event.EventID()
+			}) {
+				st.events[i].published = true
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Storage) FetchUnpublishedEvents(ctx context.Context, limit int) ([]domain.Event, error) {
+	var unpublished []storedEvent
+	_ = s.do(ctx, func(st *state) error {
+		for _, stored := range st.events {
+			if !stored.published {
+				unpublished = append(unpublished, stored)
+			}
+		}
+		return nil
+	})
+
+	sortEvents(unpublished)
+	return toEvents(unpublished, limit), nil
+}
+
+// FetchEvents returns events, archived ones included, ordered by creation
+// time and ID, starting after the given position.
+func (s *Storage) FetchEvents(ctx context.Context, after time.Time, afterID uuid.UUID, limit int) ([]domain.Event, error) {
+	var events []storedEvent
+	_ = s.do(ctx, func(st *state) error {
+		for _, stored := range slices.Concat(st.events, st.archive) {
+			e := stored.event
+			if c := e.CreatedAt().Compare(after); c > 0 || c == 0 && compareIDs(e.EventID(), afterID) > 0 {
+				events = append(events, stored)
+			}
+		}
+		return nil
+	})
+
+	sortEvents(events)
+	return toEvents(events, limit), nil
+}
+
+// ArchiveEvents moves up to limit published events created before the given
+// time into the archive. Events created after the oldest unpublished one are
+// kept, since handlers that lag behind may still need them.
+func (s *Storage) ArchiveEvents(ctx context.Context, before time.Time, limit int) (int, error) {
+	var archived int
+	err := s.do(ctx, func(st *state) error {
+		for _, stored := range st.events {
+			if !stored.published && stored.event.CreatedAt().Before(before) {
+				before = stored.event.CreatedAt()
+			}
+		}
+
+		sortEvents(st.events)
+		kept := st.events[:0:0]
+		for _, stored := range st.events {
+			if archived < limit && stored.published && stored.event.CreatedAt().Before(before) {
+				st.archive = append(st.archive, stored)
+				archived++
+				continue
+			}
+			kept = append(kept, stored)
+		}
+		st.events = kept
+		return nil
+	})
+	return archived, err
+}
+
+func (s *Storage) PruneArchivedEvents(ctx context.Context, before time.Time) (int, error) {
+	var pruned int
+	err := s.do(ctx, func(st *state) error {
+		kept := st.archive[:0:0]
+		for _, stored := range st.archive {
+			if stored.event.CreatedAt().Before(before) {
+				pruned++
+				continue
+			}
+			kept = append(kept, stored)
+		}
+		st.archive = kept
+		return nil
+	})
+	return pruned, err
+}
+
+func sortEvents(events []storedEvent) {
+	slices.SortFunc(events, func(a, b storedEvent) int {
+		if c := a.event.CreatedAt().Compare(b.event.CreatedAt()); c != 0 {
+			return c
+		}
+		return compareIDs(a.event.EventID(), b.event.EventID())
+	})
+}
+
+func toEvents(stored []storedEvent, limit int) []domain.Event {
+	if len(stored) > limit {
+		stored = stored[:limit]
+	}
+
+	events := make([]domain.Event, 0, len(stored))
+	for _, s := range stored {
+		events = append(events, s.event)
+	}
+	return events
+}
@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+func (s *Storage) GetSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error) {
+	return s.getSlide(ctx, id, false)
+}
+
+func (s *Storage) GetDeletedSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error) {
+	return s.getSlide(ctx, id, true)
+}
+
+func (s *Storage) getSlide(ctx context.Context, id uuid.UUID, deleted bool) (domain.Slide, error) {
+	var slide domain.Slide
+	err := s.do(ctx, func(st *state) error {
+		stored, ok := st.slides[id]
+		if !ok || stored.DeletedAt.IsZero() == deleted || (!deleted && !st.slideVisible(stored)) {
+			return domain.ErrSlideNotFound
+		}
+		slide = cloneSlide(stored)
+		return nil
+	})
+	return slide, err
+}
+
+func (s *Storage) GetSlideByBarcode(ctx context.Context, barcode string) (domain.Slide, error) {
+	slides := s.selectSlides(ctx, func(st *state, slide domain.Slide) bool {
+		return slide.Barcode == barcode && st.slideVisible(slide)
+	})
+	if len(slides) == 0 {
+		return domain.Slide{}, domain.ErrSlideNotFound
+	}
+	return slides[0], nil
+}
+
+func (s *Storage) GetSlidesByBarcodes(ctx context.Context, barcodes []string) ([]domain.Slide, error) {
+	return s.selectSlides(ctx, func(st *state, slide domain.Slide) bool {
+		return slices.Contains(barcodes, slide.Barcode) && st.slideVisible(slide)
+	}), nil
+}
+
+func (s *Storage) GetSlidesByCaseID(ctx context.Context, caseID uuid.UUID) ([]domain.Slide, error) {
+	return s.selectSlides(ctx, func(st *state, slide domain.Slide) bool {
+		return slide.CaseID == caseID && st.slideVisible(slide)
+	}), nil
+}
+
+func (s *Storage) GetProcessingSlidesStartedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error) {
+	slides := s.selectSlides(ctx, func(st *state, slide domain.Slide) bool {
+		return slide.PreparationStatus == domain.SlidePreparationStatusProcessing &&
+			slide.StartedAt.Before(before) &&
+			st.slideVisible(slide)
+	})
+	slices.SortStableFunc(slides, func(a, b domain.Slide) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return slides, nil
+}
+
+func (s *Storage) GetSlidesWithLeaseExpiredBefore(ctx context.Context, before time.Time) ([]domain.Slide, error) {
+	slides := s.selectSlides(ctx, func(st *state, slide domain.Slide) bool {
+		return slide.PreparationStatus == domain.SlidePreparationStatusProcessing &&
+			!slide.LeaseExpiresAt.IsZero() &&
+			slide.LeaseExpiresAt.Before(before) &&
+			st.slideVisible(slide)
+	})
+	slices.SortStableFunc(slides, func(a, b domain.Slide) int {
+		return a.LeaseExpiresAt.Compare(b.LeaseExpiresAt)
+	})
+	return slides, nil
+}
+
+func (s *Storage) GetSlidesDeletedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error) {
+	slides := s.selectSlides(ctx, func(_ *state, slide domain.Slide) bool {
+		return !slide.DeletedAt.IsZero() && slide.DeletedAt.Before(before)
+	})
+	slices.SortStableFunc(slides, func(a, b domain.Slide) int {
+		return a.DeletedAt.Compare(b.DeletedAt)
+	})
+	return slides, nil
+}
+
+// GetNextQueuedSlide returns the not started slide of an open case that should
+// be processed next: highest case priority first, then the oldest slide.
+func (s *Storage) GetNextQueuedSlide(ctx context.Context) (domain.Slide, error) {
+	var (
+		next     domain.Slide
+		priority domain.CasePriority
+		found    bool
+	)
+
+	_ = s.do(ctx, func(st *state) error {
+		for _, slide := range st.slides {
+			c := st.cases[slide.CaseID]
+			if !st.slideVisible(slide) || c.Status != domain.CaseStatusOpen {
+				continue
+			}
+			if slide.PreparationStatus != domain.SlidePreparationStatusNotStarted {
+				continue
+			}
+
+			if !found || queuedBefore(c.Priority, slide, priority, next) {
+				next, priority, found = slide, c.Priority, true
+			}
+		}
+		return nil
+	})
+
+	if !found {
+		return domain.Slide{}, domain.ErrNoQueuedSlides
+	}
+	return cloneSlide(next), nil
+}
+
+func queuedBefore(priority domain.CasePriority, slide domain.Slide, otherPriority domain.CasePriority, other domain.Slide) bool {
+	if priority != otherPriority {
+		return priority > otherPriority
+	}
+	if c := slide.CreatedAt.Compare(other.CreatedAt); c != 0 {
+		return c < 0
+	}
+	return compareIDs(slide.ID, other.ID) < 0
+}
+
+func (s *Storage) SaveSlide(ctx context.Context, slide domain.Slide) error {
+	return s.do(ctx, func(st *state) error {
+		return st.saveSlide(slide)
+	})
+}
+
+// SaveSlides inserts new slides. Slides that were already stored have to be
+// saved one by one with SaveSlide.
+func (s *Storage) SaveSlides(ctx context.Context, slides []domain.Slide) error {
+	for _, slide := range slides {
+		if slide.Version != 0 {
+			return fmt.Errorf("save slides: slide %s is already stored", slide.ID)
+		}
+	}
+
+	return s.WithTx(ctx, func(ctx context.Context) error {
+		return s.do(ctx, func(st *state) error {
+			for _, slide := range slides {
+				if err := st.saveSlide(slide); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// DeleteSlide removes the slide for good. Soft deletion goes through
+// SaveSlide like any other change.
+func (s *Storage) DeleteSlide(ctx context.Context, slide domain.Slide) error {
+	return s.do(ctx, func(st *state) error {
+		stored, ok := st.slides[slide.ID]
+		if !ok || stored.Version != slide.Version {
+			return domain.ErrVersionConflict
+		}
+		delete(st.slides, slide.ID)
+		return nil
+	})
+}
+
+func (st *state) saveSlide(slide domain.Slide) error {
+	stored, ok := st.slides[slide.ID]
+	if slide.Version == 0 {
+		if ok {
+			return domain.ErrVersionConflict
+		}
+	} else if !ok || stored.Version != slide.Version {
+		return domain.ErrVersionConflict
+	}
+
+	if _, ok := st.cases[slide.CaseID]; !ok {
+		return domain.ErrCaseNotFound
+	}
+	if !st.blockExists(slide.BlockID) {
+		return domain.ErrBlockNotFound
+	}
+	for _, other := range st.slides {
+		if other.ID != slide.ID && other.Barcode == slide.Barcode {
+			return domain.ErrSlideBarcodeTaken
+		}
+	}
+
+	slide = cloneSlide(slide)
+	slide.Version++
+	st.slides[slide.ID] = slide
+	return nil
+}
+
+// slideVisible reports whether the slide and its case are not deleted.
+// Slides of a deleted case keep their own DeletedAt unset, so restoring
+// the case brings them back.
+func (st *state) slideVisible(slide domain.Slide) bool {
+	c, ok := st.cases[slide.CaseID]
+	return ok && c.DeletedAt.IsZero() && slide.DeletedAt.IsZero()
+}
+
+func (s *Storage) selectSlides(ctx context.Context, match func(st *state, slide domain.Slide) bool) []domain.Slide {
+	var slides []domain.Slide
+	_ = s.do(ctx, func(st *state) error {
+		for _, slide := range st.slides {
+			if match(st, slide) {
+				slides = append(slides, cloneSlide(slide))
+			}
+		}
+		return nil
+	})
+
+	slices.SortFunc(slides, func(a, b domain.Slide) int {
+		return compareIDs(a.ID, b.ID)
+	})
+	return slides
+}
+
+func cloneSlide(slide domain.Slide) domain.Slide {
+	slide.PullEvents()
+	return slide
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+type txKey struct{}
+
+// Storage keeps aggregates and events in memory. It follows the psql
+// adapter: versions are checked on update, deleted rows are hidden from the
+// regular lookups and WithTx discards every change when fn fails.
+// Transactions are serialized.
+type Storage struct {
+	mu    sync.Mutex
+	state *state
+}
+
+func NewStorage() *Storage {
+	return &Storage{state: newState()}
+}
+
+type state struct {
+	cases   map[uuid.UUID]domain.Case
+	slides  map[uuid.UUID]domain.Slide
+	events  []storedEvent
+	archive []storedEvent
+}
+
+func newState() *state {
+	return &state{
+		cases:  make(map[uuid.UUID]domain.Case),
+		slides: make(map[uuid.UUID]domain.Slide),
+	}
+}
+
+// clone copies the state shallowly. Stored aggregates are never modified in
+// place, so they can be shared between the copies.
+func (s *state) clone() *state {
+	return &state{
+		cases:   maps.Clone(s.cases),
+		slides:  maps.Clone(s.slides),
+		events:  slices.Clone(s.events),
+		archive: slices.Clone(s.archive),
+	}
+}
+
+// WithTx runs fn on a copy of the state that replaces it only when fn
+// succeeds. Nested calls work on a copy of the enclosing transaction, which
+// behaves like a savepoint. Transactions are serialized, so isolation levels
+// need no handling.
+func (s *Storage) WithTx(ctx context.Context, fn func(ctx context.Context) error, opts ...application.TxOption) error {
+	o := application.NewTxOptions(opts...)
+
+	if outer, ok := ctx.Value(txKey{}).(*txState); ok {
+		if err := o.Nested(outer.opts); err != nil {
+			return err
+		}
+
+		tx := &txState{state: outer.state.clone(), opts: outer.opts}
+		if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+			return err
+		}
+		*outer.state = *tx.state
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &txState{state: s.state.clone(), opts: o}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	s.state = tx.state
+	return nil
+}
+
+type txState struct {
+	state *state
+	opts  application.TxOptions
+}
+
+func (s *Storage) ActiveTx(ctx context.Context) (application.TxOptions, bool) {
+	if tx, ok := ctx.Value(txKey{}).(*txState); ok {
+		return tx.opts, true
+	}
+	return application.TxOptions{}, false
+}
+
+// do runs fn against the transaction state carried by ctx, or against the
+// committed state under the lock.
+func (s *Storage) do(ctx context.Context, fn func(st *state) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(tx.state)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.state)
+}
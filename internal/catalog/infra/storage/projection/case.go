@@ -2,64 +2,207 @@ package projection
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/mapping"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
 )
 
 type CaseProjector struct {
-	db      *sqlx.DB
+	db      *psqlclient.Writer
+	router  *psqlclient.Router
 	service *domain.Service
 }
 
-func NewCaseProjectior(db *sqlx.DB, service *domain.Service) *CaseProjector {
+func NewCaseProjectior(router *psqlclient.Router, service *domain.Service) *CaseProjector {
 	return &CaseProjector{
-		db:      db,
+		db:      router.Writer(),
+		router:  router,
 		service: service,
 	}
 }
 
 type CaseProjection struct {
-	ID     string `db:"id"`
-	Status uint8  `db:"status"`
+	ID                string         `db:"id"`
+	PreparationStatus uint8          `db:"preparation_status"`
+	Status            uint8          `db:"status"`
+	Priority          uint8          `db:"priority"`
+	SlidesNotStarted  int            `db:"slides_not_started"`
+	SlidesProcessing  int            `db:"slides_processing"`
+	SlidesDone        int            `db:"slides_done"`
+	SlidesError       int            `db:"slides_error"`
+	MergedInto        sql.NullString `db:"merged_into"`
+	CreatedAt         time.Time      `db:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at"`
+}
+
+type caseSlideProjection struct {
+	SlideID   string    `db:"slide_id"`
+	CaseID    string    `db:"case_id"`
+	Status    uint8     `db:"status"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Reset drops the projection, so it can be rebuilt by replaying events.
+func (p *CaseProjector) Reset(ctx context.Context) error {
+	if _, err := p.db.ExecContext(ctx, `TRUNCATE case_projections, case_projection_slides`); err != nil {
+		return fmt.Errorf("truncate case projections: %w", err)
+	}
+	return nil
+}
+
+func (p *CaseProjector) HandleCaseCreated(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventCaseCreated)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	query := `
+		INSERT INTO case_projections (id, preparation_status, status, priority, created_at, updated_at)
+		VALUES (:id, :preparation_status, :status, :priority, :created_at, :updated_at)
+		ON CONFLICT (id) DO UPDATE
+		SET priority = EXCLUDED.priority,
+			created_at = EXCLUDED.created_at
+	`
+
+	_, err := p.db.NamedExecContext(ctx, query, CaseProjection{
+		ID:                e.CaseID.String(),
+		PreparationStatus: mapping.ToModelCasePreparationStatus(domain.CasePreparationStatusNotStarted),
+		Status:            mapping.ToModelCaseStatus(domain.CaseStatusOpen),
+		Priority:          mapping.ToModelCasePriority(e.Priority),
+		CreatedAt:         e.CreationTime,
+		UpdatedAt:         e.CreationTime,
+	})
+	if err != nil {
+		return fmt.Errorf("insert case projection: %w", err)
+	}
+
+	return nil
+}
+
+func (p *CaseProjector) HandleCaseStatusChanged(ctx context.Context, event domain.Event) error {
+	caseID, status, err := toCaseStatusChange(event)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE case_projections
+		SET status = $2,
+			updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err = p.db.ExecContext(ctx, query, caseID.String(), mapping.ToModelCaseStatus(status), event.CreatedAt())
+	if err != nil {
+		return fmt.Errorf("update case projection: %w", err)
+	}
+
+	return nil
+}
+
+func (p *CaseProjector) HandleCaseMerged(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventCaseMerged)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	query := `
+		UPDATE case_projections
+		SET status = $2,
+			merged_into = $3,
+			updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := p.db.ExecContext(ctx, query,
+		e.CaseID.String(),
+		mapping.ToModelCaseStatus(domain.CaseStatusMerged),
+		e.TargetCaseID.String(),
+		e.CreationTime,
+	)
+	if err != nil {
+		return fmt.Errorf("update case projection: %w", err)
+	}
+
+	return nil
 }
 
 func (p *CaseProjector) HandleSlideCreated(ctx context.Context, event domain.Event) error {
-	projection, err := p.buildCaseProjection(event)
+	projection, slide, err := p.buildCaseProjection(event)
 	if err != nil {
 		return fmt.Errorf("build case projection: %w", err)
 	}
 
 	query := `
-		INSERT INTO case_projections (id, status)
-		VALUES (:id, :status)
+		INSERT INTO case_projections (id, preparation_status, created_at, updated_at)
+		VALUES (:id, :preparation_status, :created_at, :updated_at)
 		ON CONFLICT (id) DO NOTHING
 	`
 
-	_, err = p.db.NamedExecContext(ctx, query, projection)
+	return p.inTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.NamedExecContext(ctx, query, projection); err != nil {
+			return fmt.Errorf("insert case projection: %w", err)
+		}
+
+		return p.updateSlide(ctx, tx, projection, slide)
+	})
+}
+
+func (p *CaseProjector) HandleSlideUpdated(ctx context.Context, event domain.Event) error {
+	projection, slide, err := p.buildCaseProjection(event)
 	if err != nil {
-		return fmt.Errorf("insert case projection: %w", err)
+		return fmt.Errorf("build case projection: %w", err)
+	}
+
+	return p.inTx(ctx, func(tx *sqlx.Tx) error {
+		return p.updateSlide(ctx, tx, projection, slide)
+	})
+}
+
+func (p *CaseProjector) HandleCasePreparationStatusChanged(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventCasePreparationStatusChanged)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	query := `
+		UPDATE case_projections
+		SET preparation_status = $2,
+			updated_at = GREATEST(updated_at, $3)
+		WHERE id = $1
+	`
+
+	_, err := p.db.ExecContext(ctx, query,
+		e.CaseID.String(),
+		mapping.ToModelCasePreparationStatus(e.CasePreparationStatus),
+		e.CreationTime,
+	)
+	if err != nil {
+		return fmt.Errorf("update case projection: %w", err)
 	}
 
 	return nil
 }
 
-func (p *CaseProjector) HandleSlideUpdated(ctx context.Context, event domain.Event) error {
-	projection, err := p.buildCaseProjection(event)
+func (p *CaseProjector) HandleCaseDeletionChanged(ctx context.Context, event domain.Event) error {
+	caseID, deletedAt, err := toCaseDeletion(event)
 	if err != nil {
-		return fmt.Errorf("build case projection: %w", err)
+		return err
 	}
 
 	query := `
 		UPDATE case_projections
-		SET status = :status
-		WHERE id = :id
-		  AND status IS DISTINCT FROM :status
+		SET deleted_at = $2,
+			updated_at = GREATEST(updated_at, $3)
+		WHERE id = $1
 	`
 
-	_, err = p.db.NamedExecContext(ctx, query, projection)
+	_, err = p.db.ExecContext(ctx, query, caseID.String(), deletedAt, event.CreatedAt())
 	if err != nil {
 		return fmt.Errorf("update case projection: %w", err)
 	}
@@ -67,19 +210,162 @@ func (p *CaseProjector) HandleSlideUpdated(ctx context.Context, event domain.Eve
 	return nil
 }
 
-func (p *CaseProjector) buildCaseProjection(event domain.Event) (CaseProjection, error) {
-	switch e := event.(type) {
-	case domain.EventSlideCreated:
-		return CaseProjection{
-			ID:     e.CaseID.String(),
-			Status: mapping.ToModelCasePreparationStatus(e.CasePreparationStatus),
-		}, nil
-	case domain.EvenSlideFinished:
-		return CaseProjection{
-			ID:     e.CaseID.String(),
-			Status: mapping.ToModelCasePreparationStatus(e.CasePreparationStatus),
-		}, nil
+func (p *CaseProjector) HandleCasePurged(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventCasePurged)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	return p.inTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM case_projection_slides WHERE case_id = $1`, e.CaseID.String()); err != nil {
+			return fmt.Errorf("delete case projection slides: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM case_projections WHERE id = $1`, e.CaseID.String()); err != nil {
+			return fmt.Errorf("delete case projection: %w", err)
+		}
+		return nil
+	})
+}
+
+// HandleSlideDeleted drops the slide from the case counts. A restored slide
+// comes back through HandleSlideUpdated.
+func (p *CaseProjector) HandleSlideDeleted(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventSlideDeleted)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	projection := CaseProjection{
+		ID:                e.CaseID.String(),
+		PreparationStatus: mapping.ToModelCasePreparationStatus(e.CasePreparationStatus),
+		UpdatedAt:         e.CreationTime,
+	}
+
+	return p.inTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM case_projection_slides WHERE slide_id = $1`, e.SlideID.String()); err != nil {
+			return fmt.Errorf("delete case projection slide: %w", err)
+		}
+		return p.recountSlides(ctx, tx, projection)
+	})
+}
+
+// HandleSlideMoved moves the slide row to the target case and recounts both
+// cases, so the source no longer accounts for the slide.
+func (p *CaseProjector) HandleSlideMoved(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventSlideMoved)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	source := CaseProjection{
+		ID:                e.FromCaseID.String(),
+		PreparationStatus: mapping.ToModelCasePreparationStatus(e.SourceCasePreparationStatus),
+		UpdatedAt:         e.CreationTime,
+	}
+	target := CaseProjection{
+		ID:                e.ToCaseID.String(),
+		PreparationStatus: mapping.ToModelCasePreparationStatus(e.TargetCasePreparationStatus),
+		UpdatedAt:         e.CreationTime,
+	}
+	slide := caseSlideProjection{
+		SlideID:   e.SlideID.String(),
+		CaseID:    e.ToCaseID.String(),
+		Status:    mapping.ToModelSlidePreparationStatus(e.SlidePreparationStatus),
+		UpdatedAt: e.CreationTime,
+	}
+
+	return p.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := p.updateSlide(ctx, tx, target, slide); err != nil {
+			return err
+		}
+		return p.recountSlides(ctx, tx, source)
+	})
+}
+
+// updateSlide stores the slide status and recounts case slides by status, so
+// replaying the same event twice leaves the projection unchanged.
+func (p *CaseProjector) updateSlide(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	projection CaseProjection,
+	slide caseSlideProjection,
+) error {
+	slideQuery := `
+		INSERT INTO case_projection_slides (slide_id, case_id, status, updated_at)
+		VALUES (:slide_id, :case_id, :status, :updated_at)
+		ON CONFLICT (slide_id) DO UPDATE
+		SET case_id = EXCLUDED.case_id,
+			status = EXCLUDED.status,
+			updated_at = EXCLUDED.updated_at
+		WHERE case_projection_slides.updated_at <= EXCLUDED.updated_at
+	`
+
+	if _, err := tx.NamedExecContext(ctx, slideQuery, slide); err != nil {
+		return fmt.Errorf("upsert case projection slide: %w", err)
+	}
+
+	return p.recountSlides(ctx, tx, projection)
+}
+
+func (p *CaseProjector) recountSlides(ctx context.Context, tx *sqlx.Tx, projection CaseProjection) error {
+	query := `
+		UPDATE case_projections cp
+		SET preparation_status = :preparation_status,
+			updated_at = GREATEST(cp.updated_at, :updated_at),
+			slides_not_started = c.not_started,
+			slides_processing = c.processing,
+			slides_done = c.done,
+			slides_error = c.error
+		FROM (
+			SELECT
+				COUNT(*) FILTER (WHERE status = 1) AS not_started,
+				COUNT(*) FILTER (WHERE status = 2) AS processing,
+				COUNT(*) FILTER (WHERE status = 3) AS done,
+				COUNT(*) FILTER (WHERE status = 4) AS error
+			FROM case_projection_slides
+			WHERE case_id = :id
+		) c
+		WHERE cp.id = :id
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, projection); err != nil {
+		return fmt.Errorf("update case projection: %w", err)
+	}
+
+	return nil
+}
+
+func (p *CaseProjector) inTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := fn(tx.Tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (p *CaseProjector) buildCaseProjection(event domain.Event) (CaseProjection, caseSlideProjection, error) {
+	change, err := toSlideChange(event)
+	if err != nil {
+		return CaseProjection{}, caseSlideProjection{}, err
+	}
+
+	projection := CaseProjection{
+		ID:                change.caseID.String(),
+		PreparationStatus: mapping.ToModelCasePreparationStatus(change.caseStatus),
+		CreatedAt:         event.CreatedAt(),
+		UpdatedAt:         event.CreatedAt(),
+	}
+	slide := caseSlideProjection{
+		SlideID:   change.slideID.String(),
+		CaseID:    change.caseID.String(),
+		Status:    mapping.ToModelSlidePreparationStatus(change.slideStatus),
+		UpdatedAt: event.CreatedAt(),
 	}
 
-	return CaseProjection{}, fmt.Errorf("unknown event: %s", event.Name())
+	return projection, slide, nil
 }
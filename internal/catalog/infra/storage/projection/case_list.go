@@ -0,0 +1,192 @@
+package projection
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/mapping"
+)
+
+// caseCursor points at the last case of a page. Value is the sort column
+// value of that case rendered as text.
+type caseCursor struct {
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (p *CaseProjector) ListCases(ctx context.Context, q application.CaseListQuery) (application.CasePage, error) {
+	sortColumn := caseSortColumn(q.Sort)
+
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if q.Status != domain.CaseStatusUnknown {
+		conditions = append(conditions, "status = "+arg(mapping.ToModelCaseStatus(q.Status)))
+	}
+	if q.PreparationStatus != domain.CasePreparationStatusUnkown {
+		conditions = append(conditions, "preparation_status = "+arg(mapping.ToModelCasePreparationStatus(q.PreparationStatus)))
+	}
+	if q.Priority != domain.CasePriorityUnknown {
+		conditions = append(conditions, "priority = "+arg(mapping.ToModelCasePriority(q.Priority)))
+	}
+	if !q.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(q.CreatedFrom))
+	}
+	if !q.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(q.CreatedTo))
+	}
+
+	order, cmp := "ASC", ">"
+	if q.Descending {
+		order, cmp = "DESC", "<"
+	}
+
+	if q.Cursor != "" {
+		cursor, value, err := decodeCaseCursor(q.Cursor, q.Sort)
+		if err != nil {
+			return application.CasePage{}, err
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s, %s)", sortColumn, cmp, arg(value), arg(cursor.ID.String())))
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	query := fmt.Sprintf(`
+		SELECT id, preparation_status, status, priority,
+			slides_not_started, slides_processing, slides_done, slides_error,
+			merged_into, created_at, updated_at
+		FROM case_projections
+		%s
+		ORDER BY %s %s, id %s
+		LIMIT %s
+	`, where, sortColumn, order, order, arg(q.Limit+1))
+
+	var projections []CaseProjection
+	if err := p.router.Reader(ctx).SelectContext(ctx, &projections, query, args...); err != nil {
+		return application.CasePage{}, fmt.Errorf("select case projections: %w", err)
+	}
+
+	var page application.CasePage
+	if len(projections) > q.Limit {
+		projections = projections[:q.Limit]
+		page.NextCursor = encodeCaseCursor(projections[len(projections)-1], q.Sort)
+	}
+
+	page.Cases = make([]application.CaseSummary, 0, len(projections))
+	for _, projection := range projections {
+		page.Cases = append(page.Cases, projection.toSummary())
+	}
+
+	return page, nil
+}
+
+func (p *CaseProjector) GetCase(ctx context.Context, caseID uuid.UUID) (application.CaseSummary, error) {
+	query := `
+		SELECT id, preparation_status, status, priority,
+			slides_not_started, slides_processing, slides_done, slides_error,
+			merged_into, created_at, updated_at
+		FROM case_projections
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var projection CaseProjection
+	if err := p.router.Reader(ctx).GetContext(ctx, &projection, query, caseID.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return application.CaseSummary{}, domain.ErrCaseNotFound
+		}
+		return application.CaseSummary{}, fmt.Errorf("select case projection: %w", err)
+	}
+
+	return projection.toSummary(), nil
+}
+
+func (p CaseProjection) toSummary() application.CaseSummary {
+	id, _ := uuid.Parse(p.ID)
+	mergedInto, _ := uuid.Parse(p.MergedInto.String)
+
+	return application.CaseSummary{
+		ID:                id,
+		Status:            mapping.ToDomainCaseStatus(p.Status),
+		PreparationStatus: mapping.ToDomainCasePreparationStatus(p.PreparationStatus),
+		Priority:          mapping.ToDomainCasePriority(p.Priority),
+		SlideCounts: application.SlideCounts{
+			NotStarted: p.SlidesNotStarted,
+			Processing: p.SlidesProcessing,
+			Done:       p.SlidesDone,
+			Error:      p.SlidesError,
+		},
+		MergedInto: mergedInto,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+	}
+}
+
+func caseSortColumn(sort application.CaseSort) string {
+	switch sort {
+	case application.CaseSortUpdatedAt:
+		return "updated_at"
+	case application.CaseSortPriority:
+		return "priority"
+	}
+	return "created_at"
+}
+
+func encodeCaseCursor(last CaseProjection, sort application.CaseSort) string {
+	id, _ := uuid.Parse(last.ID)
+	cursor := caseCursor{ID: id}
+
+	switch sort {
+	case application.CaseSortUpdatedAt:
+		cursor.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	case application.CaseSortPriority:
+		cursor.Value = strconv.Itoa(int(last.Priority))
+	default:
+		cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCaseCursor(raw string, sort application.CaseSort) (caseCursor, any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return caseCursor{}, nil, application.ErrInvalidCursor
+	}
+
+	var cursor caseCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return caseCursor{}, nil, application.ErrInvalidCursor
+	}
+
+	switch sort {
+	case application.CaseSortPriority:
+		priority, err := strconv.Atoi(cursor.Value)
+		if err != nil {
+			return caseCursor{}, nil, application.ErrInvalidCursor
+		}
+		return cursor, priority, nil
+	default:
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return caseCursor{}, nil, application.ErrInvalidCursor
+		}
+		return cursor, t, nil
+	}
+}
@@ -0,0 +1,72 @@
+package projection
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+// slideChange is what read models need to know about an event that changed
+// a slide preparation status.
+type slideChange struct {
+	slideID     uuid.UUID
+	caseID      uuid.UUID
+	slideStatus domain.SlidePreparationStatus
+	caseStatus  domain.CasePreparationStatus
+}
+
+func toSlideChange(event domain.Event) (slideChange, error) {
+	switch e := event.(type) {
+	case domain.EventSlideCreated:
+		return slideChange{e.SlideID, e.CaseID, domain.SlidePreparationStatusNotStarted, e.CasePreparationStatus}, nil
+	case domain.EventSlideStarted:
+		return slideChange{e.SlideID, e.CaseID, domain.SlidePreparationStatusProcessing, e.CasePreparationStatus}, nil
+	case domain.EvenSlideFinished:
+		return slideChange{e.SlideID, e.CaseID, domain.SlidePreparationStatusDone, e.CasePreparationStatus}, nil
+	case domain.EventSlideFailed:
+		return slideChange{e.SlideID, e.CaseID, domain.SlidePreparationStatusError, e.CasePreparationStatus}, nil
+	case domain.EventSlideTimedOut:
+		return slideChange{e.SlideID, e.CaseID, domain.SlidePreparationStatusError, e.CasePreparationStatus}, nil
+	case domain.EventSlideReleased:
+		return slideChange{e.SlideID, e.CaseID, domain.SlidePreparationStatusNotStarted, e.CasePreparationStatus}, nil
+	case domain.EventSlideRestored:
+		return slideChange{e.SlideID, e.CaseID, e.SlidePreparationStatus, e.CasePreparationStatus}, nil
+	}
+
+	return slideChange{}, fmt.Errorf("unknown event: %s", event.Name())
+}
+
+// toCaseStatusChange maps case lifecycle events to the status they lead to.
+func toCaseStatusChange(event domain.Event) (uuid.UUID, domain.CaseStatus, error) {
+	switch e := event.(type) {
+	case domain.EventCaseSignedOut:
+		return e.CaseID, domain.CaseStatusSignedOut, nil
+	case domain.EventCaseHeld:
+		return e.CaseID, domain.CaseStatusOnHold, nil
+	case domain.EventCaseHoldReleased:
+		return e.CaseID, domain.CaseStatusOpen, nil
+	case domain.EventCaseCancelled:
+		return e.CaseID, domain.CaseStatusCancelled, nil
+	case domain.EventCaseReopened:
+		return e.CaseID, domain.CaseStatusOpen, nil
+	case domain.EventCaseMerged:
+		return e.CaseID, domain.CaseStatusMerged, nil
+	}
+
+	return uuid.Nil, domain.CaseStatusUnknown, fmt.Errorf("unknown event: %s", event.Name())
+}
+
+// toCaseDeletion maps case deletion events to the deletion time they lead to,
+// which is NULL once the case is restored.
+func toCaseDeletion(event domain.Event) (uuid.UUID, sql.NullTime, error) {
+	switch e := event.(type) {
+	case domain.EventCaseDeleted:
+		return e.CaseID, sql.NullTime{Time: e.CreationTime, Valid: true}, nil
+	case domain.EventCaseRestored:
+		return e.CaseID, sql.NullTime{}, nil
+	}
+
+	return uuid.Nil, sql.NullTime{}, fmt.Errorf("unknown event: %s", event.Name())
+}
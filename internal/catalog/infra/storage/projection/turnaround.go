@@ -0,0 +1,281 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/mapping"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
+)
+
+type TurnaroundProjector struct {
+	db     *psqlclient.Writer
+	router *psqlclient.Router
+}
+
+func NewTurnaroundProjector(router *psqlclient.Router) *TurnaroundProjector {
+	return &TurnaroundProjector{db: router.Writer(), router: router}
+}
+
+type SlideTurnaroundProjection struct {
+	SlideID      string    `db:"slide_id"`
+	CaseID       string    `db:"case_id"`
+	StainType    uint8     `db:"stain_type"`
+	StainName    string    `db:"stain_name"`
+	CasePriority uint8     `db:"case_priority"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type turnaroundStatsRow struct {
+	Key           string  `db:"key"`
+	Count         int     `db:"count"`
+	MedianSeconds float64 `db:"median_seconds"`
+	P90Seconds    float64 `db:"p90_seconds"`
+}
+
+func (p *TurnaroundProjector) Reset(ctx context.Context) error {
+	if _, err := p.db.ExecContext(ctx, `TRUNCATE slide_turnarounds`); err != nil {
+		return fmt.Errorf("truncate slide turnarounds: %w", err)
+	}
+	return nil
+}
+
+func (p *TurnaroundProjector) HandleSlideCreated(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventSlideCreated)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	query := `
+		INSERT INTO slide_turnarounds (slide_id, case_id, stain_type, stain_name, case_priority, created_at)
+		VALUES (:slide_id, :case_id, :stain_type, :stain_name, :case_priority, :created_at)
+		ON CONFLICT (slide_id) DO NOTHING
+	`
+
+	_, err := p.db.NamedExecContext(ctx, query, SlideTurnaroundProjection{
+		SlideID:      e.SlideID.String(),
+		CaseID:       e.CaseID.String(),
+		StainType:    mapping.ToModelStainType(e.Stain.Type),
+		StainName:    e.Stain.Name,
+		CasePriority: mapping.ToModelCasePriority(e.CasePriority),
+		CreatedAt:    e.CreationTime,
+	})
+	if err != nil {
+		return fmt.Errorf("insert slide turnaround: %w", err)
+	}
+
+	return nil
+}
+
+func (p *TurnaroundProjector) HandleSlideUpdated(ctx context.Context, event domain.Event) error {
+	var (
+		query   string
+		slideID uuid.UUID
+	)
+
+	switch e := event.(type) {
+	case domain.EventSlideStarted:
+		slideID = e.SlideID
+		query = `UPDATE slide_turnarounds SET started_at = $2 WHERE slide_id = $1`
+	case domain.EvenSlideFinished:
+		slideID = e.SlideID
+		query = `UPDATE slide_turnarounds SET finished_at = $2 WHERE slide_id = $1`
+	case domain.EventSlideFailed:
+		slideID = e.SlideID
+		query = `UPDATE slide_turnarounds SET failed_at = $2 WHERE slide_id = $1`
+	case domain.EventSlideTimedOut:
+		slideID = e.SlideID
+		query = `UPDATE slide_turnarounds SET failed_at = $2 WHERE slide_id = $1`
+	case domain.EventSlideReleased:
+		slideID = e.SlideID
+		query = `UPDATE slide_turnarounds SET started_at = NULL WHERE slide_id = $1 AND started_at <= $2`
+	default:
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	_, err := p.db.ExecContext(ctx, query, slideID.String(), event.CreatedAt())
+	if err != nil {
+		return fmt.Errorf("update slide turnaround: %w", err)
+	}
+
+	return nil
+}
+
+func (p *TurnaroundProjector) HandleSlideMoved(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventSlideMoved)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	query := `UPDATE slide_turnarounds SET case_id = $2, case_priority = $3 WHERE slide_id = $1`
+
+	_, err := p.db.ExecContext(ctx, query, e.SlideID.String(), e.ToCaseID.String(), mapping.ToModelCasePriority(e.CasePriority))
+	if err != nil {
+		return fmt.Errorf("update slide turnaround: %w", err)
+	}
+
+	return nil
+}
+
+// HandleSlideDeletionChanged excludes deleted slides from the statistics and
+// brings them back once restored.
+func (p *TurnaroundProjector) HandleSlideDeletionChanged(ctx context.Context, event domain.Event) error {
+	var (
+		query   string
+		slideID uuid.UUID
+	)
+
+	switch e := event.(type) {
+	case domain.EventSlideDeleted:
+		slideID = e.SlideID
+		query = `UPDATE slide_turnarounds SET deleted_at = $2 WHERE slide_id = $1`
+	case domain.EventSlideRestored:
+		slideID = e.SlideID
+		query = `UPDATE slide_turnarounds SET deleted_at = NULL WHERE slide_id = $1 AND deleted_at <= $2`
+	default:
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	_, err := p.db.ExecContext(ctx, query, slideID.String(), event.CreatedAt())
+	if err != nil {
+		return fmt.Errorf("update slide turnaround: %w", err)
+	}
+
+	return nil
+}
+
+func (p *TurnaroundProjector) HandleCaseDeletionChanged(ctx context.Context, event domain.Event) error {
+	caseID, deletedAt, err := toCaseDeletion(event)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE slide_turnarounds SET case_deleted_at = $2 WHERE case_id = $1`
+
+	_, err = p.db.ExecContext(ctx, query, caseID.String(), deletedAt)
+	if err != nil {
+		return fmt.Errorf("update slide turnarounds: %w", err)
+	}
+
+	return nil
+}
+
+func (p *TurnaroundProjector) HandlePurged(ctx context.Context, event domain.Event) error {
+	var err error
+
+	switch e := event.(type) {
+	case domain.EventSlidePurged:
+		_, err = p.db.ExecContext(ctx, `DELETE FROM slide_turnarounds WHERE slide_id = $1`, e.SlideID.String())
+	case domain.EventCasePurged:
+		_, err = p.db.ExecContext(ctx, `DELETE FROM slide_turnarounds WHERE case_id = $1`, e.CaseID.String())
+	default:
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+	if err != nil {
+		return fmt.Errorf("delete slide turnarounds: %w", err)
+	}
+
+	return nil
+}
+
+func (p *TurnaroundProjector) CaseTurnaround(
+	ctx context.Context,
+	filter application.TurnaroundFilter,
+) ([]application.CaseTurnaroundStats, error) {
+	rows, err := p.selectStats(ctx, "case_id::TEXT", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]application.CaseTurnaroundStats, 0, len(rows))
+	for _, row := range rows {
+		caseID, _ := uuid.Parse(row.Key)
+		stats = append(stats, application.CaseTurnaroundStats{
+			CaseID:          caseID,
+			TurnaroundStats: row.toStats(),
+		})
+	}
+
+	return stats, nil
+}
+
+func (p *TurnaroundProjector) DailyTurnaround(
+	ctx context.Context,
+	filter application.TurnaroundFilter,
+) ([]application.DailyTurnaroundStats, error) {
+	rows, err := p.selectStats(ctx, "to_char(date_trunc('day', finished_at), 'YYYY-MM-DD')", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]application.DailyTurnaroundStats, 0, len(rows))
+	for _, row := range rows {
+		day, err := time.Parse(time.DateOnly, row.Key)
+		if err != nil {
+			return nil, fmt.Errorf("parse day: %w", err)
+		}
+		stats = append(stats, application.DailyTurnaroundStats{
+			Day:             day,
+			TurnaroundStats: row.toStats(),
+		})
+	}
+
+	return stats, nil
+}
+
+func (p *TurnaroundProjector) selectStats(
+	ctx context.Context,
+	groupBy string,
+	filter application.TurnaroundFilter,
+) ([]turnaroundStatsRow, error) {
+	conditions := []string{"finished_at IS NOT NULL", "deleted_at IS NULL", "case_deleted_at IS NULL"}
+	var args []any
+
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("finished_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("finished_at < $%d", len(args)))
+	}
+	if filter.StainType != domain.StainTypeUnknown {
+		args = append(args, mapping.ToModelStainType(filter.StainType))
+		conditions = append(conditions, fmt.Sprintf("stain_type = $%d", len(args)))
+	}
+	if filter.Priority != domain.CasePriorityUnknown {
+		args = append(args, mapping.ToModelCasePriority(filter.Priority))
+		conditions = append(conditions, fmt.Sprintf("case_priority = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %[1]s AS key,
+			COUNT(*) AS count,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM finished_at - created_at)) AS median_seconds,
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM finished_at - created_at)) AS p90_seconds
+		FROM slide_turnarounds
+		WHERE %[2]s
+		GROUP BY %[1]s
+		ORDER BY %[1]s
+	`, groupBy, strings.Join(conditions, " AND "))
+
+	var rows []turnaroundStatsRow
+	if err := p.router.Reader(ctx).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("select turnaround stats: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r turnaroundStatsRow) toStats() application.TurnaroundStats {
+	return application.TurnaroundStats{
+		Count:  r.Count,
+		Median: time.Duration(r.MedianSeconds * float64(time.Second)),
+		P90:    time.Duration(r.P90Seconds * float64(time.Second)),
+	}
+}
@@ -0,0 +1,230 @@
+package projection
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/mapping"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
+)
+
+type WorklistProjector struct {
+	db     *psqlclient.Writer
+	router *psqlclient.Router
+}
+
+func NewWorklistProjector(router *psqlclient.Router) *WorklistProjector {
+	return &WorklistProjector{db: router.Writer(), router: router}
+}
+
+type worklistItemProjection struct {
+	CaseID     string       `db:"case_id"`
+	Priority   uint8        `db:"priority"`
+	AssignedAt sql.NullTime `db:"assigned_at"`
+	ReadyAt    sql.NullTime `db:"ready_at"`
+}
+
+func (p *WorklistProjector) Reset(ctx context.Context) error {
+	if _, err := p.db.ExecContext(ctx, `TRUNCATE pathologist_worklist`); err != nil {
+		return fmt.Errorf("truncate worklist: %w", err)
+	}
+	return nil
+}
+
+func (p *WorklistProjector) HandleCaseCreated(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventCaseCreated)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	query := `
+		INSERT INTO pathologist_worklist (case_id, pathologist, status, priority, preparation_status)
+		VALUES ($1, '', $2, $3, $4)
+		ON CONFLICT (case_id) DO UPDATE
+		SET priority = EXCLUDED.priority
+	`
+
+	_, err := p.db.ExecContext(ctx, query,
+		e.CaseID.String(),
+		mapping.ToModelCaseStatus(domain.CaseStatusOpen),
+		mapping.ToModelCasePriority(e.Priority),
+		mapping.ToModelCasePreparationStatus(domain.CasePreparationStatusNotStarted),
+	)
+	if err != nil {
+		return fmt.Errorf("insert worklist item: %w", err)
+	}
+
+	return nil
+}
+
+func (p *WorklistProjector) HandleCaseAssigned(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventCaseAssigned)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	query := `
+		UPDATE pathologist_worklist
+		SET pathologist = $2,
+			assigned_at = $3
+		WHERE case_id = $1
+	`
+
+	_, err := p.db.ExecContext(ctx, query, e.CaseID.String(), e.Pathologist, e.CreationTime)
+	if err != nil {
+		return fmt.Errorf("update worklist item: %w", err)
+	}
+
+	return nil
+}
+
+func (p *WorklistProjector) HandleCaseStatusChanged(ctx context.Context, event domain.Event) error {
+	caseID, status, err := toCaseStatusChange(event)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE pathologist_worklist SET status = $2 WHERE case_id = $1`
+
+	_, err = p.db.ExecContext(ctx, query, caseID.String(), mapping.ToModelCaseStatus(status))
+	if err != nil {
+		return fmt.Errorf("update worklist item: %w", err)
+	}
+
+	return nil
+}
+
+// HandleSlideUpdated tracks the case preparation status. ready_at keeps the
+// time the case first became Done and is reset once it is not Done anymore.
+func (p *WorklistProjector) HandleSlideUpdated(ctx context.Context, event domain.Event) error {
+	change, err := toSlideChange(event)
+	if err != nil {
+		return err
+	}
+
+	return p.updatePreparationStatus(ctx, change.caseID, change.caseStatus, event.CreatedAt())
+}
+
+func (p *WorklistProjector) HandleCasePreparationStatusChanged(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventCasePreparationStatusChanged)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	return p.updatePreparationStatus(ctx, e.CaseID, e.CasePreparationStatus, e.CreationTime)
+}
+
+func (p *WorklistProjector) HandleSlideDeleted(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventSlideDeleted)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	return p.updatePreparationStatus(ctx, e.CaseID, e.CasePreparationStatus, e.CreationTime)
+}
+
+func (p *WorklistProjector) HandleCaseDeletionChanged(ctx context.Context, event domain.Event) error {
+	caseID, deletedAt, err := toCaseDeletion(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.ExecContext(ctx, `UPDATE pathologist_worklist SET deleted_at = $2 WHERE case_id = $1`, caseID.String(), deletedAt)
+	if err != nil {
+		return fmt.Errorf("update worklist item: %w", err)
+	}
+
+	return nil
+}
+
+func (p *WorklistProjector) HandleCasePurged(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventCasePurged)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	_, err := p.db.ExecContext(ctx, `DELETE FROM pathologist_worklist WHERE case_id = $1`, e.CaseID.String())
+	if err != nil {
+		return fmt.Errorf("delete worklist item: %w", err)
+	}
+
+	return nil
+}
+
+func (p *WorklistProjector) HandleSlideMoved(ctx context.Context, event domain.Event) error {
+	e, ok := event.(domain.EventSlideMoved)
+	if !ok {
+		return fmt.Errorf("unknown event: %s", event.Name())
+	}
+
+	if err := p.updatePreparationStatus(ctx, e.FromCaseID, e.SourceCasePreparationStatus, e.CreationTime); err != nil {
+		return err
+	}
+	return p.updatePreparationStatus(ctx, e.ToCaseID, e.TargetCasePreparationStatus, e.CreationTime)
+}
+
+func (p *WorklistProjector) updatePreparationStatus(
+	ctx context.Context,
+	caseID uuid.UUID,
+	status domain.CasePreparationStatus,
+	at time.Time,
+) error {
+	query := `
+		UPDATE pathologist_worklist
+		SET preparation_status = $2,
+			ready_at = CASE
+				WHEN $2 <> $3 THEN NULL
+				ELSE COALESCE(ready_at, $4)
+			END
+		WHERE case_id = $1
+	`
+
+	_, err := p.db.ExecContext(ctx, query,
+		caseID.String(),
+		mapping.ToModelCasePreparationStatus(status),
+		mapping.ToModelCasePreparationStatus(domain.CasePreparationStatusDone),
+		at,
+	)
+	if err != nil {
+		return fmt.Errorf("update worklist item: %w", err)
+	}
+
+	return nil
+}
+
+func (p *WorklistProjector) Worklist(ctx context.Context, pathologist string) ([]application.WorklistItem, error) {
+	query := `
+		SELECT case_id, priority, assigned_at, ready_at
+		FROM pathologist_worklist
+		WHERE pathologist = $1 AND status = $2 AND preparation_status = $3 AND deleted_at IS NULL
+		ORDER BY priority DESC, ready_at
+	`
+
+	var rows []worklistItemProjection
+	err := p.router.Reader(ctx).SelectContext(ctx, &rows, query,
+		pathologist,
+		mapping.ToModelCaseStatus(domain.CaseStatusOpen),
+		mapping.ToModelCasePreparationStatus(domain.CasePreparationStatusDone),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select worklist: %w", err)
+	}
+
+	items := make([]application.WorklistItem, 0, len(rows))
+	for _, row := range rows {
+		caseID, _ := uuid.Parse(row.CaseID)
+		items = append(items, application.WorklistItem{
+			CaseID:     caseID,
+			Priority:   mapping.ToDomainCasePriority(row.Priority),
+			AssignedAt: row.AssignedAt.Time,
+			ReadyAt:    row.ReadyAt.Time,
+		})
+	}
+
+	return items, nil
+}
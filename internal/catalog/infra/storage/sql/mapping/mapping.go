@@ -1,47 +1,203 @@
 package mapping
 
 import (
+	"database/sql"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
 )
 
 type CaseModel struct {
-	ID      string `db:"id"`
-	Version int    `db:"version"`
+	ID          string         `db:"id"`
+	Version     int            `db:"version"`
+	Priority    uint8          `db:"priority"`
+	Status      uint8          `db:"status"`
+	Pathologist string         `db:"pathologist"`
+	MergedInto  sql.NullString `db:"merged_into"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
+}
+
+type CaseAssignmentModel struct {
+	ID          string    `db:"id"`
+	CaseID      string    `db:"case_id"`
+	Pathologist string    `db:"pathologist"`
+	AssignedAt  time.Time `db:"assigned_at"`
 }
 
-func ToDomainCase(model CaseModel) domain.Case {
+type SpecimenModel struct {
+	ID          string `db:"id"`
+	CaseID      string `db:"case_id"`
+	Label       string `db:"label"`
+	Description string `db:"description"`
+}
+
+type BlockModel struct {
+	ID         string `db:"id"`
+	SpecimenID string `db:"specimen_id"`
+	Label      string `db:"label"`
+}
+
+func ToDomainCase(
+	model CaseModel,
+	specimens []SpecimenModel,
+	blocks []BlockModel,
+	assignments []CaseAssignmentModel,
+) domain.Case {
 	uid, _ := uuid.Parse(model.ID)
+	mergedInto, _ := uuid.Parse(model.MergedInto.String)
+
+	blocksBySpecimen := make(map[string][]domain.Block)
+	for _, b := range blocks {
+		blocksBySpecimen[b.SpecimenID] = append(blocksBySpecimen[b.SpecimenID], ToDomainBlock(b))
+	}
+
+	domainSpecimens := make([]domain.Specimen, 0, len(specimens))
+	for _, sp := range specimens {
+		specimen := ToDomainSpecimen(sp)
+		specimen.Blocks = blocksBySpecimen[sp.ID]
+		domainSpecimens = append(domainSpecimens, specimen)
+	}
+
+	domainAssignments := make([]domain.CaseAssignment, 0, len(assignments))
+	for _, a := range assignments {
+		domainAssignments = append(domainAssignments, ToDomainCaseAssignment(a))
+	}
 
 	return domain.Case{
-		ID:      uid,
-		Version: domain.Version(model.Version),
+		ID:          uid,
+		Version:     domain.Version(model.Version),
+		Priority:    ToDomainCasePriority(model.Priority),
+		Status:      ToDomainCaseStatus(model.Status),
+		Specimens:   domainSpecimens,
+		Pathologist: model.Pathologist,
+		Assignments: domainAssignments,
+		MergedInto:  mergedInto,
+		DeletedAt:   fromNullTime(model.DeletedAt),
 	}
 }
 
 func ToModelCase(c domain.Case) CaseModel {
 	return CaseModel{
-		ID:      c.ID.String(),
-		Version: int(c.Version),
+		ID:          c.ID.String(),
+		Version:     int(c.Version),
+		Priority:    ToModelCasePriority(c.Priority),
+		Status:      ToModelCaseStatus(c.Status),
+		Pathologist: c.Pathologist,
+		MergedInto: sql.NullString{
+			String: c.MergedInto.String(),
+			Valid:  c.MergedInto != uuid.Nil,
+		},
+		DeletedAt: toNullTime(c.DeletedAt),
+	}
+}
+
+func ToDomainCaseAssignment(model CaseAssignmentModel) domain.CaseAssignment {
+	uid, _ := uuid.Parse(model.ID)
+
+	return domain.CaseAssignment{
+		ID:          uid,
+		Pathologist: model.Pathologist,
+		AssignedAt:  model.AssignedAt,
+	}
+}
+
+func ToModelCaseAssignment(caseID uuid.UUID, a domain.CaseAssignment) CaseAssignmentModel {
+	return CaseAssignmentModel{
+		ID:          a.ID.String(),
+		CaseID:      caseID.String(),
+		Pathologist: a.Pathologist,
+		AssignedAt:  a.AssignedAt,
+	}
+}
+
+func ToDomainSpecimen(model SpecimenModel) domain.Specimen {
+	uid, _ := uuid.Parse(model.ID)
+	caseuid, _ := uuid.Parse(model.CaseID)
+
+	return domain.Specimen{
+		ID:          uid,
+		CaseID:      caseuid,
+		Label:       model.Label,
+		Description: model.Description,
+	}
+}
+
+func ToModelSpecimen(specimen domain.Specimen) SpecimenModel {
+	return SpecimenModel{
+		ID:          specimen.ID.String(),
+		CaseID:      specimen.CaseID.String(),
+		Label:       specimen.Label,
+		Description: specimen.Description,
+	}
+}
+
+func ToDomainBlock(model BlockModel) domain.Block {
+	uid, _ := uuid.Parse(model.ID)
+	specimenuid, _ := uuid.Parse(model.SpecimenID)
+
+	return domain.Block{
+		ID:         uid,
+		SpecimenID: specimenuid,
+		Label:      model.Label,
+	}
+}
+
+func ToModelBlock(block domain.Block) BlockModel {
+	return BlockModel{
+		ID:         block.ID.String(),
+		SpecimenID: block.SpecimenID.String(),
+		Label:      block.Label,
 	}
 }
 
 type SlideModel struct {
-	ID                string `db:"id"`
-	Version           int    `db:"version"`
-	PreparationStatus uint8  `db:"preparation_status"`
-	CaseID            string `db:"case_id"`
+	ID                string         `db:"id"`
+	Version           int            `db:"version"`
+	PreparationStatus uint8          `db:"preparation_status"`
+	CaseID            string         `db:"case_id"`
+	StainType         uint8          `db:"stain_type"`
+	StainName         string         `db:"stain_name"`
+	BlockID           sql.NullString `db:"block_id"`
+	Level             int            `db:"level"`
+	Barcode           string         `db:"barcode"`
+	Label             string         `db:"label"`
+	CreatedAt         sql.NullTime   `db:"created_at"`
+	StartedAt         sql.NullTime   `db:"started_at"`
+	FinishedAt        sql.NullTime   `db:"finished_at"`
+	FailedAt          sql.NullTime   `db:"failed_at"`
+	FailureReason     string         `db:"failure_reason"`
+	AssignedTo        string         `db:"assigned_to"`
+	LeaseExpiresAt    sql.NullTime   `db:"lease_expires_at"`
+	DeletedAt         sql.NullTime   `db:"deleted_at"`
 }
 
 func ToDomainSlide(model SlideModel) domain.Slide {
 	uid, _ := uuid.Parse(model.ID)
 	caseuid, _ := uuid.Parse(model.CaseID)
+	blockuid, _ := uuid.Parse(model.BlockID.String)
 
 	return domain.Slide{
 		ID:                uid,
 		Version:           domain.Version(model.Version),
 		PreparationStatus: ToDomainSlidePreparationStatus(model.PreparationStatus),
 		CaseID:            caseuid,
+		Stain: domain.Stain{
+			Type: ToDomainStainType(model.StainType),
+			Name: model.StainName,
+		},
+		BlockID:        blockuid,
+		Level:          model.Level,
+		Barcode:        model.Barcode,
+		Label:          model.Label,
+		CreatedAt:      fromNullTime(model.CreatedAt),
+		StartedAt:      fromNullTime(model.StartedAt),
+		FinishedAt:     fromNullTime(model.FinishedAt),
+		FailedAt:       fromNullTime(model.FailedAt),
+		FailureReason:  model.FailureReason,
+		AssignedTo:     model.AssignedTo,
+		LeaseExpiresAt: fromNullTime(model.LeaseExpiresAt),
+		DeletedAt:      fromNullTime(model.DeletedAt),
 	}
 }
 
@@ -51,6 +207,23 @@ func ToModelSlide(slide domain.Slide) SlideModel {
 		Version:           int(slide.Version),
 		PreparationStatus: ToModelSlidePreparationStatus(slide.PreparationStatus),
 		CaseID:            slide.CaseID.String(),
+		StainType:         ToModelStainType(slide.Stain.Type),
+		StainName:         slide.Stain.Name,
+		Level:             slide.Level,
+		Barcode:           slide.Barcode,
+		Label:             slide.Label,
+		CreatedAt:         toNullTime(slide.CreatedAt),
+		StartedAt:         toNullTime(slide.StartedAt),
+		FinishedAt:        toNullTime(slide.FinishedAt),
+		FailedAt:          toNullTime(slide.FailedAt),
+		FailureReason:     slide.FailureReason,
+		AssignedTo:        slide.AssignedTo,
+		LeaseExpiresAt:    toNullTime(slide.LeaseExpiresAt),
+		DeletedAt:         toNullTime(slide.DeletedAt),
+		BlockID: sql.NullString{
+			String: slide.BlockID.String(),
+			Valid:  slide.BlockID != uuid.Nil,
+		},
 	}
 }
 
@@ -109,3 +282,94 @@ func ToModelCasePreparationStatus(status domain.CasePreparationStatus) uint8 {
 	}
 	return 0
 }
+
+func ToDomainStainType(stainType uint8) domain.StainType {
+	switch stainType {
+	case 1:
+		return domain.StainTypeHE
+	case 2:
+		return domain.StainTypeIHC
+	case 3:
+		return domain.StainTypeSpecial
+	}
+	return domain.StainTypeUnknown
+}
+
+func ToModelStainType(stainType domain.StainType) uint8 {
+	switch stainType {
+	case domain.StainTypeHE:
+		return 1
+	case domain.StainTypeIHC:
+		return 2
+	case domain.StainTypeSpecial:
+		return 3
+	}
+	return 0
+}
+
+func ToDomainCasePriority(priority uint8) domain.CasePriority {
+	switch priority {
+	case 1:
+		return domain.CasePriorityRoutine
+	case 2:
+		return domain.CasePriorityUrgent
+	case 3:
+		return domain.CasePriorityStat
+	}
+	return domain.CasePriorityUnknown
+}
+
+func ToModelCasePriority(priority domain.CasePriority) uint8 {
+	switch priority {
+	case domain.CasePriorityRoutine:
+		return 1
+	case domain.CasePriorityUrgent:
+		return 2
+	case domain.CasePriorityStat:
+		return 3
+	}
+	return 0
+}
+
+func ToDomainCaseStatus(status uint8) domain.CaseStatus {
+	switch status {
+	case 1:
+		return domain.CaseStatusOpen
+	case 2:
+		return domain.CaseStatusOnHold
+	case 3:
+		return domain.CaseStatusSignedOut
+	case 4:
+		return domain.CaseStatusCancelled
+	case 5:
+		return domain.CaseStatusMerged
+	}
+	return domain.CaseStatusUnknown
+}
+
+func ToModelCaseStatus(status domain.CaseStatus) uint8 {
+	switch status {
+	case domain.CaseStatusOpen:
+		return 1
+	case domain.CaseStatusOnHold:
+		return 2
+	case domain.CaseStatusSignedOut:
+		return 3
+	case domain.CaseStatusCancelled:
+		return 4
+	case domain.CaseStatusMerged:
+		return 5
+	}
+	return 0
+}
+
+func toNullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func fromNullTime(t sql.NullTime) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+	return t.Time
+}
@@ -22,10 +22,140 @@ func ToModelEvent(e domain.Event, published bool) (EventModel, error) {
 
 	switch evt := e.(type) {
 	case domain.EventSlideCreated:
+		payload["slide_id"] = evt.SlideID
 		payload["case_id"] = evt.CaseID
+		payload["case_priority"] = evt.CasePriority
+		payload["stain_type"] = evt.Stain.Type
+		payload["stain_name"] = evt.Stain.Name
+		payload["case_preparation_status"] = evt.CasePreparationStatus
+
+	case domain.EventSlideStarted:
+		payload["slide_id"] = evt.SlideID
+		payload["case_id"] = evt.CaseID
+		payload["assigned_to"] = evt.AssignedTo
+		payload["lease_expires_at"] = evt.LeaseExpiresAt
+		payload["case_preparation_status"] = evt.CasePreparationStatus
+
+	case domain.EventSlideLeaseRenewed:
+		payload["slide_id"] = evt.SlideID
+		payload["case_id"] = evt.CaseID
+		payload["assigned_to"] = evt.AssignedTo
+		payload["lease_expires_at"] = evt.LeaseExpiresAt
+
+	case domain.EventSlideReleased:
+		payload["slide_id"] = evt.SlideID
+		payload["case_id"] = evt.CaseID
+		payload["assigned_to"] = evt.AssignedTo
+		payload["expired"] = evt.Expired
 		payload["case_preparation_status"] = evt.CasePreparationStatus
 
 	case domain.EvenSlideFinished:
+		payload["slide_id"] = evt.SlideID
+		payload["case_id"] = evt.CaseID
+		payload["case_preparation_status"] = evt.CasePreparationStatus
+
+	case domain.EventSlideFailed:
+		payload["slide_id"] = evt.SlideID
+		payload["case_id"] = evt.CaseID
+		payload["reason"] = evt.Reason
+		payload["case_preparation_status"] = evt.CasePreparationStatus
+
+	case domain.EventSlideTimedOut:
+		payload["slide_id"] = evt.SlideID
+		payload["case_id"] = evt.CaseID
+		payload["timeout"] = evt.Timeout
+		payload["reason"] = evt.Reason
+		payload["case_preparation_status"] = evt.CasePreparationStatus
+
+	case domain.EventSlideMoved:
+		payload["slide_id"] = evt.SlideID
+		payload["from_case_id"] = evt.FromCaseID
+		payload["to_case_id"] = evt.ToCaseID
+		payload["block_id"] = evt.BlockID
+		payload["label"] = evt.Label
+		payload["case_priority"] = evt.CasePriority
+		payload["slide_preparation_status"] = evt.SlidePreparationStatus
+		payload["source_case_preparation_status"] = evt.SourceCasePreparationStatus
+		payload["target_case_preparation_status"] = evt.TargetCasePreparationStatus
+
+	case domain.EventSpecimenAdded:
+		payload["case_id"] = evt.CaseID
+		payload["specimen_id"] = evt.SpecimenID
+		payload["label"] = evt.Label
+
+	case domain.EventBlockAdded:
+		payload["case_id"] = evt.CaseID
+		payload["specimen_id"] = evt.SpecimenID
+		payload["block_id"] = evt.BlockID
+		payload["label"] = evt.Label
+
+	case domain.EventCaseCreated:
+		payload["case_id"] = evt.CaseID
+		payload["priority"] = evt.Priority
+
+	case domain.EventCaseSignedOut:
+		payload["case_id"] = evt.CaseID
+		payload["pathologist"] = evt.Pathologist
+
+	case domain.EventCaseAssigned:
+		payload["case_id"] = evt.CaseID
+		payload["pathologist"] = evt.Pathologist
+		payload["previous_pathologist"] = evt.PreviousPathologist
+
+	case domain.EventCaseHeld:
+		payload["case_id"] = evt.CaseID
+		payload["reason"] = evt.Reason
+
+	case domain.EventCaseHoldReleased:
+		payload["case_id"] = evt.CaseID
+
+	case domain.EventCaseCancelled:
+		payload["case_id"] = evt.CaseID
+		payload["reason"] = evt.Reason
+
+	case domain.EventCaseReopened:
+		payload["case_id"] = evt.CaseID
+		payload["reason"] = evt.Reason
+
+	case domain.EventCaseMerged:
+		payload["case_id"] = evt.CaseID
+		payload["target_case_id"] = evt.TargetCaseID
+
+	case domain.EventCaseSplit:
+		payload["case_id"] = evt.CaseID
+		payload["new_case_id"] = evt.NewCaseID
+		payload["slide_ids"] = evt.SlideIDs
+
+	case domain.EventSlideDeleted:
+		payload["slide_id"] = evt.SlideID
+		payload["case_id"] = evt.CaseID
+		payload["reason"] = evt.Reason
+		payload["case_preparation_status"] = evt.CasePreparationStatus
+
+	case domain.EventSlideRestored:
+		payload["slide_id"] = evt.SlideID
+		payload["case_id"] = evt.CaseID
+		payload["slide_preparation_status"] = evt.SlidePreparationStatus
+		payload["case_preparation_status"] = evt.CasePreparationStatus
+
+	case domain.EventSlidePurged:
+		payload["slide_id"] = evt.SlideID
+		payload["case_id"] = evt.CaseID
+		payload["barcode"] = evt.Barcode
+		payload["deleted_at"] = evt.DeletedAt
+
+	case domain.EventCaseDeleted:
+		payload["case_id"] = evt.CaseID
+		payload["reason"] = evt.Reason
+
+	case domain.EventCaseRestored:
+		payload["case_id"] = evt.CaseID
+
+	case domain.EventCasePurged:
+		payload["case_id"] = evt.CaseID
+		payload["deleted_at"] = evt.DeletedAt
+
+	case domain.EventCasePreparationStatusChanged:
 		payload["case_id"] = evt.CaseID
 		payload["case_preparation_status"] = evt.CasePreparationStatus
 
@@ -54,7 +184,11 @@ func ToDomainEvent(event EventModel) (domain.Event, error) {
 
 	case domain.EventTypeSlideCreated:
 		var payload struct {
+			SlideID               uuid.UUID                    `json:"slide_id"`
 			CaseID                uuid.UUID                    `json:"case_id"`
+			CasePriority          domain.CasePriority          `json:"case_priority"`
+			StainType             domain.StainType             `json:"stain_type"`
+			StainName             string                       `json:"stain_name"`
 			CasePreparationStatus domain.CasePreparationStatus `json:"case_preparation_status"`
 		}
 
@@ -63,14 +197,88 @@ func ToDomainEvent(event EventModel) (domain.Event, error) {
 		}
 
 		return domain.EventSlideCreated{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			SlideID:      payload.SlideID,
+			CaseID:       payload.CaseID,
+			CasePriority: payload.CasePriority,
+			Stain: domain.Stain{
+				Type: payload.StainType,
+				Name: payload.StainName,
+			},
+			CasePreparationStatus: payload.CasePreparationStatus,
+		}, nil
+
+	case domain.EventTypeSlideStarted:
+		var payload struct {
+			SlideID               uuid.UUID                    `json:"slide_id"`
+			CaseID                uuid.UUID                    `json:"case_id"`
+			AssignedTo            string                       `json:"assigned_to"`
+			LeaseExpiresAt        time.Time                    `json:"lease_expires_at"`
+			CasePreparationStatus domain.CasePreparationStatus `json:"case_preparation_status"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSlideStarted: %w", err)
+		}
+
+		return domain.EventSlideStarted{
+			ID:                    event.ID,
+			CreationTime:          event.CreatedAt,
+			SlideID:               payload.SlideID,
+			CaseID:                payload.CaseID,
+			AssignedTo:            payload.AssignedTo,
+			LeaseExpiresAt:        payload.LeaseExpiresAt,
+			CasePreparationStatus: payload.CasePreparationStatus,
+		}, nil
+
+	case domain.EventTypeSlideLeaseRenewed:
+		var payload struct {
+			SlideID        uuid.UUID `json:"slide_id"`
+			CaseID         uuid.UUID `json:"case_id"`
+			AssignedTo     string    `json:"assigned_to"`
+			LeaseExpiresAt time.Time `json:"lease_expires_at"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSlideLeaseRenewed: %w", err)
+		}
+
+		return domain.EventSlideLeaseRenewed{
+			ID:             event.ID,
+			CreationTime:   event.CreatedAt,
+			SlideID:        payload.SlideID,
+			CaseID:         payload.CaseID,
+			AssignedTo:     payload.AssignedTo,
+			LeaseExpiresAt: payload.LeaseExpiresAt,
+		}, nil
+
+	case domain.EventTypeSlideReleased:
+		var payload struct {
+			SlideID               uuid.UUID                    `json:"slide_id"`
+			CaseID                uuid.UUID                    `json:"case_id"`
+			AssignedTo            string                       `json:"assigned_to"`
+			Expired               bool                         `json:"expired"`
+			CasePreparationStatus domain.CasePreparationStatus `json:"case_preparation_status"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSlideReleased: %w", err)
+		}
+
+		return domain.EventSlideReleased{
 			ID:                    event.ID,
 			CreationTime:          event.CreatedAt,
+			SlideID:               payload.SlideID,
 			CaseID:                payload.CaseID,
+			AssignedTo:            payload.AssignedTo,
+			Expired:               payload.Expired,
 			CasePreparationStatus: payload.CasePreparationStatus,
 		}, nil
 
 	case domain.EventTypeSlideFinished:
 		var payload struct {
+			SlideID               uuid.UUID                    `json:"slide_id"`
 			CaseID                uuid.UUID                    `json:"case_id"`
 			CasePreparationStatus domain.CasePreparationStatus `json:"case_preparation_status"`
 		}
@@ -80,6 +288,406 @@ func ToDomainEvent(event EventModel) (domain.Event, error) {
 		}
 
 		return domain.EvenSlideFinished{
+			ID:                    event.ID,
+			CreationTime:          event.CreatedAt,
+			SlideID:               payload.SlideID,
+			CaseID:                payload.CaseID,
+			CasePreparationStatus: payload.CasePreparationStatus,
+		}, nil
+
+	case domain.EventTypeSlideFailed:
+		var payload struct {
+			SlideID               uuid.UUID                    `json:"slide_id"`
+			CaseID                uuid.UUID                    `json:"case_id"`
+			Reason                string                       `json:"reason"`
+			CasePreparationStatus domain.CasePreparationStatus `json:"case_preparation_status"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSlideFailed: %w", err)
+		}
+
+		return domain.EventSlideFailed{
+			ID:                    event.ID,
+			CreationTime:          event.CreatedAt,
+			SlideID:               payload.SlideID,
+			CaseID:                payload.CaseID,
+			Reason:                payload.Reason,
+			CasePreparationStatus: payload.CasePreparationStatus,
+		}, nil
+
+	case domain.EventTypeSlideTimedOut:
+		var payload struct {
+			SlideID               uuid.UUID                    `json:"slide_id"`
+			CaseID                uuid.UUID                    `json:"case_id"`
+			Timeout               time.Duration                `json:"timeout"`
+			Reason                string                       `json:"reason"`
+			CasePreparationStatus domain.CasePreparationStatus `json:"case_preparation_status"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSlideTimedOut: %w", err)
+		}
+
+		return domain.EventSlideTimedOut{
+			ID:                    event.ID,
+			CreationTime:          event.CreatedAt,
+			SlideID:               payload.SlideID,
+			CaseID:                payload.CaseID,
+			Timeout:               payload.Timeout,
+			Reason:                payload.Reason,
+			CasePreparationStatus: payload.CasePreparationStatus,
+		}, nil
+
+	case domain.EventTypeSlideMoved:
+		var payload struct {
+			SlideID                     uuid.UUID                     `json:"slide_id"`
+			FromCaseID                  uuid.UUID                     `json:"from_case_id"`
+			ToCaseID                    uuid.UUID                     `json:"to_case_id"`
+			BlockID                     uuid.UUID                     `json:"block_id"`
+			Label                       string                        `json:"label"`
+			CasePriority                domain.CasePriority           `json:"case_priority"`
+			SlidePreparationStatus      domain.SlidePreparationStatus `json:"slide_preparation_status"`
+			SourceCasePreparationStatus domain.CasePreparationStatus  `json:"source_case_preparation_status"`
+			TargetCasePreparationStatus domain.CasePreparationStatus  `json:"target_case_preparation_status"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSlideMoved: %w", err)
+		}
+
+		return domain.EventSlideMoved{
+			ID:                          event.ID,
+			CreationTime:                event.CreatedAt,
+			SlideID:                     payload.SlideID,
+			FromCaseID:                  payload.FromCaseID,
+			ToCaseID:                    payload.ToCaseID,
+			BlockID:                     payload.BlockID,
+			Label:                       payload.Label,
+			CasePriority:                payload.CasePriority,
+			SlidePreparationStatus:      payload.SlidePreparationStatus,
+			SourceCasePreparationStatus: payload.SourceCasePreparationStatus,
+			TargetCasePreparationStatus: payload.TargetCasePreparationStatus,
+		}, nil
+
+	case domain.EventTypeSpecimenAdded:
+		var payload struct {
+			CaseID     uuid.UUID `json:"case_id"`
+			SpecimenID uuid.UUID `json:"specimen_id"`
+			Label      string    `json:"label"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSpecimenAdded: %w", err)
+		}
+
+		return domain.EventSpecimenAdded{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			SpecimenID:   payload.SpecimenID,
+			Label:        payload.Label,
+		}, nil
+
+	case domain.EventTypeBlockAdded:
+		var payload struct {
+			CaseID     uuid.UUID `json:"case_id"`
+			SpecimenID uuid.UUID `json:"specimen_id"`
+			BlockID    uuid.UUID `json:"block_id"`
+			Label      string    `json:"label"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventBlockAdded: %w", err)
+		}
+
+		return domain.EventBlockAdded{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			SpecimenID:   payload.SpecimenID,
+			BlockID:      payload.BlockID,
+			Label:        payload.Label,
+		}, nil
+
+	case domain.EventTypeCaseCreated:
+		var payload struct {
+			CaseID   uuid.UUID           `json:"case_id"`
+			Priority domain.CasePriority `json:"priority"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseCreated: %w", err)
+		}
+
+		return domain.EventCaseCreated{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			Priority:     payload.Priority,
+		}, nil
+
+	case domain.EventTypeCaseSignedOut:
+		var payload struct {
+			CaseID      uuid.UUID `json:"case_id"`
+			Pathologist string    `json:"pathologist"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseSignedOut: %w", err)
+		}
+
+		return domain.EventCaseSignedOut{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			Pathologist:  payload.Pathologist,
+		}, nil
+
+	case domain.EventTypeCaseAssigned:
+		var payload struct {
+			CaseID              uuid.UUID `json:"case_id"`
+			Pathologist         string    `json:"pathologist"`
+			PreviousPathologist string    `json:"previous_pathologist"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseAssigned: %w", err)
+		}
+
+		return domain.EventCaseAssigned{
+			ID:                  event.ID,
+			CreationTime:        event.CreatedAt,
+			CaseID:              payload.CaseID,
+			Pathologist:         payload.Pathologist,
+			PreviousPathologist: payload.PreviousPathologist,
+		}, nil
+
+	case domain.EventTypeCaseHeld:
+		var payload struct {
+			CaseID uuid.UUID `json:"case_id"`
+			Reason string    `json:"reason"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseHeld: %w", err)
+		}
+
+		return domain.EventCaseHeld{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			Reason:       payload.Reason,
+		}, nil
+
+	case domain.EventTypeCaseHoldReleased:
+		var payload struct {
+			CaseID uuid.UUID `json:"case_id"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseHoldReleased: %w", err)
+		}
+
+		return domain.EventCaseHoldReleased{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+		}, nil
+
+	case domain.EventTypeCaseCancelled:
+		var payload struct {
+			CaseID uuid.UUID `json:"case_id"`
+			Reason string    `json:"reason"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseCancelled: %w", err)
+		}
+
+		return domain.EventCaseCancelled{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			Reason:       payload.Reason,
+		}, nil
+
+	case domain.EventTypeCaseReopened:
+		var payload struct {
+			CaseID uuid.UUID `json:"case_id"`
+			Reason string    `json:"reason"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseReopened: %w", err)
+		}
+
+		return domain.EventCaseReopened{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			Reason:       payload.Reason,
+		}, nil
+
+	case domain.EventTypeCaseMerged:
+		var payload struct {
+			CaseID       uuid.UUID `json:"case_id"`
+			TargetCaseID uuid.UUID `json:"target_case_id"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseMerged: %w", err)
+		}
+
+		return domain.EventCaseMerged{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			TargetCaseID: payload.TargetCaseID,
+		}, nil
+
+	case domain.EventTypeCaseSplit:
+		var payload struct {
+			CaseID    uuid.UUID   `json:"case_id"`
+			NewCaseID uuid.UUID   `json:"new_case_id"`
+			SlideIDs  []uuid.UUID `json:"slide_ids"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseSplit: %w", err)
+		}
+
+		return domain.EventCaseSplit{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			NewCaseID:    payload.NewCaseID,
+			SlideIDs:     payload.SlideIDs,
+		}, nil
+
+	case domain.EventTypeSlideDeleted:
+		var payload struct {
+			SlideID               uuid.UUID                    `json:"slide_id"`
+			CaseID                uuid.UUID                    `json:"case_id"`
+			Reason                string                       `json:"reason"`
+			CasePreparationStatus domain.CasePreparationStatus `json:"case_preparation_status"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSlideDeleted: %w", err)
+		}
+
+		return domain.EventSlideDeleted{
+			ID:                    event.ID,
+			CreationTime:          event.CreatedAt,
+			SlideID:               payload.SlideID,
+			CaseID:                payload.CaseID,
+			Reason:                payload.Reason,
+			CasePreparationStatus: payload.CasePreparationStatus,
+		}, nil
+
+	case domain.EventTypeSlideRestored:
+		var payload struct {
+			SlideID                uuid.UUID                     `json:"slide_id"`
+			CaseID                 uuid.UUID                     `json:"case_id"`
+			SlidePreparationStatus domain.SlidePreparationStatus `json:"slide_preparation_status"`
+			CasePreparationStatus  domain.CasePreparationStatus  `json:"case_preparation_status"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSlideRestored: %w", err)
+		}
+
+		return domain.EventSlideRestored{
+			ID:                     event.ID,
+			CreationTime:           event.CreatedAt,
+			SlideID:                payload.SlideID,
+			CaseID:                 payload.CaseID,
+			SlidePreparationStatus: payload.SlidePreparationStatus,
+			CasePreparationStatus:  payload.CasePreparationStatus,
+		}, nil
+
+	case domain.EventTypeSlidePurged:
+		var payload struct {
+			SlideID   uuid.UUID `json:"slide_id"`
+			CaseID    uuid.UUID `json:"case_id"`
+			Barcode   string    `json:"barcode"`
+			DeletedAt time.Time `json:"deleted_at"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventSlidePurged: %w", err)
+		}
+
+		return domain.EventSlidePurged{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			SlideID:      payload.SlideID,
+			CaseID:       payload.CaseID,
+			Barcode:      payload.Barcode,
+			DeletedAt:    payload.DeletedAt,
+		}, nil
+
+	case domain.EventTypeCaseDeleted:
+		var payload struct {
+			CaseID uuid.UUID `json:"case_id"`
+			Reason string    `json:"reason"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseDeleted: %w", err)
+		}
+
+		return domain.EventCaseDeleted{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			Reason:       payload.Reason,
+		}, nil
+
+	case domain.EventTypeCaseRestored:
+		var payload struct {
+			CaseID uuid.UUID `json:"case_id"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCaseRestored: %w", err)
+		}
+
+		return domain.EventCaseRestored{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+		}, nil
+
+	case domain.EventTypeCasePurged:
+		var payload struct {
+			CaseID    uuid.UUID `json:"case_id"`
+			DeletedAt time.Time `json:"deleted_at"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCasePurged: %w", err)
+		}
+
+		return domain.EventCasePurged{
+			ID:           event.ID,
+			CreationTime: event.CreatedAt,
+			CaseID:       payload.CaseID,
+			DeletedAt:    payload.DeletedAt,
+		}, nil
+
+	case domain.EventTypeCasePreparationStatusChanged:
+		var payload struct {
+			CaseID                uuid.UUID                    `json:"case_id"`
+			CasePreparationStatus domain.CasePreparationStatus `json:"case_preparation_status"`
+		}
+
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal payload for EventCasePreparationStatusChanged: %w", err)
+		}
+
+		return domain.EventCasePreparationStatusChanged{
 			ID:                    event.ID,
 			CreationTime:          event.CreatedAt,
 			CaseID:                payload.CaseID,
@@ -97,6 +705,54 @@ func toModelEventType(et domain.EventType) uint8 {
 		return 1
 	case domain.EventTypeSlideFinished:
 		return 2
+	case domain.EventTypeSpecimenAdded:
+		return 3
+	case domain.EventTypeBlockAdded:
+		return 4
+	case domain.EventTypeSlideStarted:
+		return 5
+	case domain.EventTypeSlideFailed:
+		return 6
+	case domain.EventTypeSlideTimedOut:
+		return 7
+	case domain.EventTypeCaseCreated:
+		return 8
+	case domain.EventTypeCaseSignedOut:
+		return 9
+	case domain.EventTypeCaseHeld:
+		return 10
+	case domain.EventTypeCaseHoldReleased:
+		return 11
+	case domain.EventTypeCaseCancelled:
+		return 12
+	case domain.EventTypeCaseReopened:
+		return 13
+	case domain.EventTypeSlideLeaseRenewed:
+		return 14
+	case domain.EventTypeSlideReleased:
+		return 15
+	case domain.EventTypeCaseAssigned:
+		return 16
+	case domain.EventTypeSlideMoved:
+		return 17
+	case domain.EventTypeCaseMerged:
+		return 18
+	case domain.EventTypeCaseSplit:
+		return 19
+	case domain.EventTypeSlideDeleted:
+		return 20
+	case domain.EventTypeSlideRestored:
+		return 21
+	case domain.EventTypeSlidePurged:
+		return 22
+	case domain.EventTypeCaseDeleted:
+		return 23
+	case domain.EventTypeCaseRestored:
+		return 24
+	case domain.EventTypeCasePurged:
+		return 25
+	case domain.EventTypeCasePreparationStatusChanged:
+		return 26
 	}
 
 	return 0
@@ -108,6 +764,54 @@ func toDomainEventType(et uint8) domain.EventType {
 		return domain.EventTypeSlideCreated
 	case 2:
 		return domain.EventTypeSlideFinished
+	case 3:
+		return domain.EventTypeSpecimenAdded
+	case 4:
+		return domain.EventTypeBlockAdded
+	case 5:
+		return domain.EventTypeSlideStarted
+	case 6:
+		return domain.EventTypeSlideFailed
+	case 7:
+		return domain.EventTypeSlideTimedOut
+	case 8:
+		return domain.EventTypeCaseCreated
+	case 9:
+		return domain.EventTypeCaseSignedOut
+	case 10:
+		return domain.EventTypeCaseHeld
+	case 11:
+		return domain.EventTypeCaseHoldReleased
+	case 12:
+		return domain.EventTypeCaseCancelled
+	case 13:
+		return domain.EventTypeCaseReopened
+	case 14:
+		return domain.EventTypeSlideLeaseRenewed
+	case 15:
+		return domain.EventTypeSlideReleased
+	case 16:
+		return domain.EventTypeCaseAssigned
+	case 17:
+		return domain.EventTypeSlideMoved
+	case 18:
+		return domain.EventTypeCaseMerged
+	case 19:
+		return domain.EventTypeCaseSplit
+	case 20:
+		return domain.EventTypeSlideDeleted
+	case 21:
+		return domain.EventTypeSlideRestored
+	case 22:
+		return domain.EventTypeSlidePurged
+	case 23:
+		return domain.EventTypeCaseDeleted
+	case 24:
+		return domain.EventTypeCaseRestored
+	case 25:
+		return domain.EventTypeCasePurged
+	case 26:
+		return domain.EventTypeCasePreparationStatusChanged
 	}
 
 	return 0
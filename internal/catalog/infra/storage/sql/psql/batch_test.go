@@ -0,0 +1,116 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+var (
+	benchRows       = []int{10, 100, 1000, 5000}
+	benchBatchSizes = []int{100, 500, 1000, 2000}
+)
+
+// BenchmarkSaveSlides compares SaveSlide per slide, which is what bulk
+// creation did before multi-row inserts, with SaveSlides at several batch
+// sizes. Each op stores rows slides in one transaction.
+func BenchmarkSaveSlides(b *testing.B) {
+	for _, rows := range benchRows {
+		b.Run(fmt.Sprintf("rows=%d/per-row", rows), func(b *testing.B) {
+			benchmarkSaveSlides(b, rows, func(ctx context.Context, s *Storage, slides []domain.Slide) error {
+				for _, slide := range slides {
+					if err := s.SaveSlide(ctx, slide); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+		for _, size := range benchBatchSizes {
+			b.Run(fmt.Sprintf("rows=%d/batch=%d", rows, size), func(b *testing.B) {
+				setInsertBatchSize(b, size)
+				benchmarkSaveSlides(b, rows, func(ctx context.Context, s *Storage, slides []domain.Slide) error {
+					return s.SaveSlides(ctx, slides)
+				})
+			})
+		}
+	}
+}
+
+func benchmarkSaveSlides(b *testing.B, rows int, save func(ctx context.Context, s *Storage, slides []domain.Slide) error) {
+	s := newTestStorage(b)
+	env := domain.SystemEnv()
+	ctx := context.Background()
+
+	for b.Loop() {
+		b.StopTimer()
+		slides := saveCaseWithSlides(b, s, env, rows)
+		b.StartTimer()
+
+		err := s.WithTx(ctx, func(ctx context.Context) error {
+			return save(ctx, s, slides)
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSaveEvents compares one Add call per event with a single Add of
+// all events at several batch sizes.
+func BenchmarkSaveEvents(b *testing.B) {
+	for _, rows := range benchRows {
+		b.Run(fmt.Sprintf("rows=%d/per-row", rows), func(b *testing.B) {
+			benchmarkSaveEvents(b, rows, func(ctx context.Context, s *Storage, events []domain.Event) error {
+				for _, e := range events {
+					if err := s.AddEvent(ctx, []domain.Event{e}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+		for _, size := range benchBatchSizes {
+			b.Run(fmt.Sprintf("rows=%d/batch=%d", rows, size), func(b *testing.B) {
+				setInsertBatchSize(b, size)
+				benchmarkSaveEvents(b, rows, func(ctx context.Context, s *Storage, events []domain.Event) error {
+					return s.AddEvent(ctx, events)
+				})
+			})
+		}
+	}
+}
+
+func benchmarkSaveEvents(b *testing.B, rows int, add func(ctx context.Context, s *Storage, events []domain.Event) error) {
+	s := newTestStorage(b)
+	env := domain.SystemEnv()
+	ctx := context.Background()
+
+	for b.Loop() {
+		b.StopTimer()
+		events := make([]domain.Event, 0, rows)
+		for range rows {
+			c, err := domain.CreateCase(env, domain.CasePriorityRoutine)
+			if err != nil {
+				b.Fatal(err)
+			}
+			events = append(events, c.PullEvents()...)
+		}
+		b.StartTimer()
+
+		err := s.WithTx(ctx, func(ctx context.Context) error {
+			return add(ctx, s, events)
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func setInsertBatchSize(b *testing.B, size int) {
+	prev := insertBatchSize
+	insertBatchSize = size
+	b.Cleanup(func() { insertBatchSize = prev })
+}
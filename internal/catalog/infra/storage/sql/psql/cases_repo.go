@@ -5,30 +5,101 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/mapping"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
 )
 
 type CasesRepo struct {
-	db *sqlx.DB
+	db     *psqlclient.Writer
+	logger *slog.Logger
 }
 
-func NewCasesRepo(db *sqlx.DB) *CasesRepo {
-	return &CasesRepo{db: db}
+func NewCasesRepo(router *psqlclient.Router, logger *slog.Logger) *CasesRepo {
+	return &CasesRepo{db: router.Writer(), logger: logger}
 }
 
 func (r *CasesRepo) GetCase(ctx context.Context, id uuid.UUID) (domain.Case, error) {
+	return r.getCase(ctx, id, "deleted_at IS NULL")
+}
+
+func (r *CasesRepo) GetDeletedCase(ctx context.Context, id uuid.UUID) (domain.Case, error) {
+	return r.getCase(ctx, id, "deleted_at IS NOT NULL")
+}
+
+func (r *CasesRepo) GetCaseIDsDeletedBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	exec := executor(ctx, r.db)
+
+	var ids []uuid.UUID
+	err := exec.SelectContext(ctx, &ids, `
+		SELECT id
+		FROM cases
+		WHERE deleted_at < $1
+		ORDER BY deleted_at
+	`, before)
+	if err != nil {
+		return nil, fmt.Errorf("select deleted cases: %w", err)
+	}
+
+	return ids, nil
+}
+
+// DeleteCase removes the case for good together with its slides, specimens,
+// blocks and assignment history. A case that others were merged into is
+// kept, since dropping their reference would change them without an event.
+func (r *CasesRepo) DeleteCase(ctx context.Context, c domain.Case) error {
+	exec := executor(ctx, r.db)
+
+	var merged bool
+	err := exec.GetContext(ctx, &merged, `SELECT EXISTS (SELECT 1 FROM cases WHERE merged_into = $1)`, c.ID.String())
+	if err != nil {
+		return fmt.Errorf("check merged cases: %w", err)
+	}
+	if merged {
+		return domain.ErrCaseHasMergedCases
+	}
+
+	queries := []string{
+		`DELETE FROM slides WHERE case_id = $1`,
+		`DELETE FROM blocks WHERE specimen_id IN (SELECT id FROM specimens WHERE case_id = $1)`,
+		`DELETE FROM specimens WHERE case_id = $1`,
+		`DELETE FROM case_assignments WHERE case_id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := exec.ExecContext(ctx, query, c.ID.String()); err != nil {
+			return fmt.Errorf("delete case children: %w", err)
+		}
+	}
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM cases WHERE id = $1 AND version = $2`, c.ID.String(), int(c.Version))
+	if err != nil {
+		return fmt.Errorf("delete case: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check rows affected: %w", err)
+	}
+	if rows == 0 {
+		r.logger.WarnContext(ctx, "case version conflict", "case_id", c.ID, "version", c.Version)
+		return domain.ErrVersionConflict
+	}
+
+	return nil
+}
+
+func (r *CasesRepo) getCase(ctx context.Context, id uuid.UUID, condition string) (domain.Case, error) {
 	exec := executor(ctx, r.db)
 
 	var model mapping.CaseModel
 	err := exec.GetContext(ctx, &model, `
-		SELECT id, version
+		SELECT id, version, priority, status, pathologist, merged_into, deleted_at
 		FROM cases
-		WHERE id = $1
-	`, id.String())
+		WHERE id = $1 AND `+condition, id.String())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.Case{}, domain.ErrCaseNotFound
@@ -36,7 +107,41 @@ func (r *CasesRepo) GetCase(ctx context.Context, id uuid.UUID) (domain.Case, err
 		return domain.Case{}, fmt.Errorf("select case: %w", err)
 	}
 
-	return mapping.ToDomainCase(model), nil
+	var specimens []mapping.SpecimenModel
+	err = exec.SelectContext(ctx, &specimens, `
+		SELECT id, case_id, label, description
+		FROM specimens
+		WHERE case_id = $1
+		ORDER BY length(label), label
+	`, id.String())
+	if err != nil {
+		return domain.Case{}, fmt.Errorf("select specimens: %w", err)
+	}
+
+	var blocks []mapping.BlockModel
+	err = exec.SelectContext(ctx, &blocks, `
+		SELECT b.id, b.specimen_id, b.label
+		FROM blocks b
+		JOIN specimens s ON s.id = b.specimen_id
+		WHERE s.case_id = $1
+		ORDER BY length(b.label), b.label
+	`, id.String())
+	if err != nil {
+		return domain.Case{}, fmt.Errorf("select blocks: %w", err)
+	}
+
+	var assignments []mapping.CaseAssignmentModel
+	err = exec.SelectContext(ctx, &assignments, `
+		SELECT id, case_id, pathologist, assigned_at
+		FROM case_assignments
+		WHERE case_id = $1
+		ORDER BY assigned_at
+	`, id.String())
+	if err != nil {
+		return domain.Case{}, fmt.Errorf("select case assignments: %w", err)
+	}
+
+	return mapping.ToDomainCase(model, specimens, blocks, assignments), nil
 }
 
 func (r *CasesRepo) SaveCase(ctx context.Context, c domain.Case) error {
@@ -46,25 +151,29 @@ func (r *CasesRepo) SaveCase(ctx context.Context, c domain.Case) error {
 
 	if c.Version == 0 {
 		insertQuery := `
-			INSERT INTO cases (id, version)
-			VALUES (:id, 1)
+			INSERT INTO cases (id, version, priority, status, pathologist, merged_into, deleted_at)
+			VALUES (:id, 1, :priority, :status, :pathologist, :merged_into, :deleted_at)
 		`
 		_, err := exec.NamedExecContext(ctx, insertQuery, model)
 		if err != nil {
-			return fmt.Errorf("insert case: %w", err)
+			return fmt.Errorf("insert case: %w", translateError(err))
 		}
-		return nil
+		return r.saveChildren(ctx, exec, c)
 	}
 
 	updateQuery := `
 		UPDATE cases
-		SET version = version + 1
+		SET version = version + 1,
+			status = :status,
+			pathologist = :pathologist,
+			merged_into = :merged_into,
+			deleted_at = :deleted_at
 		WHERE id = :id AND version = :version
 	`
 
 	result, err := exec.NamedExecContext(ctx, updateQuery, model)
 	if err != nil {
-		return fmt.Errorf("update case: %w", err)
+		return fmt.Errorf("update case: %w", translateError(err))
 	}
 
 	rows, err := result.RowsAffected()
@@ -72,8 +181,65 @@ func (r *CasesRepo) SaveCase(ctx context.Context, c domain.Case) error {
 		return fmt.Errorf("check rows affected: %w", err)
 	}
 	if rows == 0 {
+		r.logger.WarnContext(ctx, "case version conflict", "case_id", c.ID, "version", c.Version)
 		return domain.ErrVersionConflict
 	}
 
+	return r.saveChildren(ctx, exec, c)
+}
+
+func (r *CasesRepo) saveChildren(ctx context.Context, exec sqlxExecutor, c domain.Case) error {
+	if err := r.saveSpecimens(ctx, exec, c); err != nil {
+		return err
+	}
+	return r.saveAssignments(ctx, exec, c)
+}
+
+func (r *CasesRepo) saveAssignments(ctx context.Context, exec sqlxExecutor, c domain.Case) error {
+	const query = `
+		INSERT INTO case_assignments (id, case_id, pathologist, assigned_at)
+		VALUES (:id, :case_id, :pathologist, :assigned_at)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	for _, a := range c.Assignments {
+		if _, err := exec.NamedExecContext(ctx, query, mapping.ToModelCaseAssignment(c.ID, a)); err != nil {
+			return fmt.Errorf("insert case assignment: %w", translateError(err))
+		}
+	}
+
+	return nil
+}
+
+func (r *CasesRepo) saveSpecimens(ctx context.Context, exec sqlxExecutor, c domain.Case) error {
+	const specimenQuery = `
+		INSERT INTO specimens (id, case_id, label, description)
+		VALUES (:id, :case_id, :label, :description)
+		ON CONFLICT (id) DO UPDATE
+		SET case_id = EXCLUDED.case_id,
+			label = EXCLUDED.label,
+			description = EXCLUDED.description
+	`
+
+	const blockQuery = `
+		INSERT INTO blocks (id, specimen_id, label)
+		VALUES (:id, :specimen_id, :label)
+		ON CONFLICT (id) DO UPDATE
+		SET specimen_id = EXCLUDED.specimen_id,
+			label = EXCLUDED.label
+	`
+
+	for _, specimen := range c.Specimens {
+		if _, err := exec.NamedExecContext(ctx, specimenQuery, mapping.ToModelSpecimen(specimen)); err != nil {
+			return fmt.Errorf("upsert specimen: %w", translateError(err))
+		}
+
+		for _, block := range specimen.Blocks {
+			if _, err := exec.NamedExecContext(ctx, blockQuery, mapping.ToModelBlock(block)); err != nil {
+				return fmt.Errorf("upsert block: %w", translateError(err))
+			}
+		}
+	}
+
 	return nil
 }
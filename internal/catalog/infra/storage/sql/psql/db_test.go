@@ -0,0 +1,111 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/migrations"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
+)
+
+// testDSNEnv names the Postgres database the tests run against, as a
+// connection URL. Its tables are emptied before every test, so it must not
+// hold data anyone cares about. Tests are skipped when it is not set.
+const testDSNEnv = "TEST_POSTGRES_DSN"
+
+var migrateOnce = sync.OnceValue(func() error {
+	db, err := sqlx.Open("pgx", os.Getenv(testDSNEnv))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	goose.SetBaseFS(migrations.FS)
+	if err := goose.SetDialect("postgres"); err != nil {
+		return err
+	}
+	return goose.Up(db.DB, ".")
+})
+
+// openTestDB connects to the test database, migrates it once per run and
+// empties every table.
+func openTestDB(tb testing.TB) *sqlx.DB {
+	tb.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		tb.Skipf("%s is not set", testDSNEnv)
+	}
+	if err := migrateOnce(); err != nil {
+		tb.Fatalf("migrate test db: %v", err)
+	}
+
+	db, err := sqlx.Open("pgx", dsn)
+	if err != nil {
+		tb.Fatalf("open test db: %v", err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`
+		TRUNCATE cases, specimens, blocks, case_assignments, slides,
+			events, events_archive,
+			case_projections, case_projection_slides, slide_turnarounds, pathologist_worklist
+		CASCADE
+	`)
+	if err != nil {
+		tb.Fatalf("truncate test db: %v", err)
+	}
+
+	return db
+}
+
+func newTestStorage(tb testing.TB) *Storage {
+	router := psqlclient.NewRouter(openTestDB(tb), nil, 0)
+	return NewStorage(router, slog.New(slog.DiscardHandler))
+}
+
+// saveCaseWithSlides stores a new case with one block and returns n slides
+// for it that are not stored yet.
+func saveCaseWithSlides(tb testing.TB, s *Storage, env domain.Env, n int) []domain.Slide {
+	tb.Helper()
+	ctx := context.Background()
+
+	c, err := domain.CreateCase(env, domain.CasePriorityRoutine)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	specimen, err := c.AddSpecimen(env, "skin")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	block, err := c.AddBlock(env, specimen.ID)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := s.SaveCase(ctx, c); err != nil {
+		tb.Fatalf("save case: %v", err)
+	}
+
+	specs := make([]domain.SlideSpec, 0, n)
+	for i := range n {
+		specs = append(specs, domain.SlideSpec{
+			Stain:   domain.Stain{Type: domain.StainTypeHE},
+			BlockID: block.ID,
+			Level:   1,
+			Barcode: fmt.Sprintf("%s-%d", c.ID, i),
+		})
+	}
+
+	slides, err := domain.NewService(env).CreateSlides(&c, nil, specs)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return slides
+}
@@ -0,0 +1,41 @@
+package psql
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+// constraintErrors maps schema constraints to the domain errors they guard
+// against, so a race that slips past the domain checks still surfaces as the
+// same error.
+var constraintErrors = map[string]error{
+	"slides_barcode_key":                 domain.ErrSlideBarcodeTaken,
+	"slides_case_id_fkey":                domain.ErrCaseNotFound,
+	"slides_block_id_fkey":               domain.ErrBlockNotFound,
+	"slides_stain_type_check":            domain.ErrInvalidSlideStain,
+	"slides_level_check":                 domain.ErrInvalidSlideLevel,
+	"slides_preparation_status_check":    domain.ErrInvalidSlideTransition,
+	"specimens_case_id_fkey":             domain.ErrCaseNotFound,
+	"blocks_specimen_id_fkey":            domain.ErrSpecimenNotFound,
+	"cases_merged_into_fkey":             domain.ErrCaseNotFound,
+	"cases_status_check":                 domain.ErrInvalidCaseTransition,
+	"cases_priority_check":               domain.ErrInvalidCasePriority,
+	"case_assignments_case_id_fkey":      domain.ErrCaseNotFound,
+	"case_assignments_pathologist_check": domain.ErrPathologistRequired,
+}
+
+// translateError replaces constraint violations with domain errors and keeps
+// the original error otherwise.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	if domainErr, ok := constraintErrors[pgErr.ConstraintName]; ok {
+		return domainErr
+	}
+	return err
+}
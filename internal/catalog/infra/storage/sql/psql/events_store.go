@@ -3,18 +3,22 @@ package psql
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
 	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/mapping"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
 )
 
 type EventsStorage struct {
-	db *sqlx.DB
+	db     *psqlclient.Writer
+	router *psqlclient.Router
 }
 
-func NewEventsStorage(db *sqlx.DB) *EventsStorage {
-	return &EventsStorage{db: db}
+func NewEventsStorage(router *psqlclient.Router) *EventsStorage {
+	return &EventsStorage{db: router.Writer(), router: router}
 }
 
 func (s *EventsStorage) MarkPublished(ctx context.Context, events []domain.Event) error {
@@ -55,7 +59,7 @@ func (s *EventsStorage) FetchUnpublished(ctx context.Context, limit int) ([]doma
 		SELECT id, type, created_at, published, payload
 		FROM events
 		WHERE NOT published
-		ORDER BY created_at
+		ORDER BY created_at, id
 		LIMIT $1
 	`
 
@@ -77,9 +81,51 @@ func (s *EventsStorage) FetchUnpublished(ctx context.Context, limit int) ([]doma
 	return domainEvents, nil
 }
 
+// Fetch returns events, archived ones included, ordered by creation time and
+// ID, starting after the given position.
+func (s *EventsStorage) Fetch(ctx context.Context, after time.Time, afterID uuid.UUID, limit int) ([]domain.Event, error) {
+	exec := reader(ctx, s.router)
+
+	const query = `
+		SELECT id, type, created_at, published, payload
+		FROM (
+			SELECT id, type, created_at, published, payload
+			FROM events
+			UNION ALL
+			SELECT id, type, created_at, true AS published, payload
+			FROM events_archive
+		) e
+		WHERE (created_at, id) > ($1, $2)
+		ORDER BY created_at, id
+		LIMIT $3
+	`
+
+	var events []mapping.EventModel
+	if err := exec.SelectContext(ctx, &events, query, after, afterID, limit); err != nil {
+		return nil, fmt.Errorf("fetch events: %w", err)
+	}
+
+	domainEvents := make([]domain.Event, 0, len(events))
+	for _, e := range events {
+		e, err := mapping.ToDomainEvent(e)
+		if err != nil {
+			return nil, fmt.Errorf("map model->domain: %w", err)
+		}
+		domainEvents = append(domainEvents, e)
+	}
+
+	return domainEvents, nil
+}
+
+// Add stores the events with multi-row inserts, so bulk operations do not
+// pay a round trip per event.
 func (s *EventsStorage) Add(ctx context.Context, events []domain.Event) error {
 	exec := executor(ctx, s.db)
 
+	if len(events) == 0 {
+		return nil
+	}
+
 	eventModels := make([]mapping.EventModel, 0, len(events))
 	for _, event := range events {
 		e, err := mapping.ToModelEvent(event, false)
@@ -94,11 +140,66 @@ func (s *EventsStorage) Add(ctx context.Context, events []domain.Event) error {
 		VALUES (:id, :type, :created_at, :published, :payload)
 	`
 
-	for _, e := range eventModels {
-		if _, err := exec.NamedExecContext(ctx, query, e); err != nil {
-			return fmt.Errorf("insert outbox event: %w", err)
-		}
+	if err := insertBatches(ctx, exec, query, eventModels); err != nil {
+		return fmt.Errorf("insert outbox events: %w", err)
 	}
 
 	return nil
 }
+
+// Archive moves up to limit published events created before the given time
+// into events_archive. Events created after the oldest unpublished one are
+// kept, since handlers that lag behind may still need them.
+func (s *EventsStorage) Archive(ctx context.Context, before time.Time, limit int) (int, error) {
+	exec := executor(ctx, s.db)
+
+	const query = `
+		WITH moved AS (
+			DELETE FROM events
+			WHERE id IN (
+				SELECT id
+				FROM events
+				WHERE published
+				  AND created_at < $1
+				  AND created_at < COALESCE(
+					(SELECT MIN(created_at) FROM events WHERE NOT published),
+					'infinity'
+				  )
+				ORDER BY created_at, id
+				LIMIT $2
+			)
+			RETURNING id, type, created_at, payload
+		)
+		INSERT INTO events_archive (id, type, created_at, payload, archived_at)
+		SELECT id, type, created_at, payload, NOW()
+		FROM moved
+	`
+
+	result, err := exec.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("archive events: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("check rows affected: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+func (s *EventsStorage) PruneArchive(ctx context.Context, before time.Time) (int, error) {
+	exec := executor(ctx, s.db)
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM events_archive WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("prune archived events: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("check rows affected: %w", err)
+	}
+
+	return int(rows), nil
+}
@@ -0,0 +1,13 @@
+package psql
+
+import (
+	"testing"
+
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/storagetest"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return newTestStorage(t)
+	})
+}
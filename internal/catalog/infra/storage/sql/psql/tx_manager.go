@@ -6,25 +6,56 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/psqlclient"
 )
 
 type txKey struct{}
 
+type txState struct {
+	tx         *sqlx.Tx
+	opts       application.TxOptions
+	savepoints int
+}
+
 type TxManager struct {
-	db *sqlx.DB
+	db *psqlclient.Writer
 }
 
-func NewTxManager(db *sqlx.DB) *TxManager {
-	return &TxManager{db: db}
+func NewTxManager(router *psqlclient.Router) *TxManager {
+	return &TxManager{db: router.Writer()}
 }
 
-func (u *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
-	tx, err := u.db.BeginTxx(ctx, nil)
+// Do runs fn in a transaction. Inside another transaction fn joins it behind
+// a savepoint, so a failing fn only undoes its own work. The transaction is
+// rolled back if fn panics. Committing a transaction that is not read-only
+// keeps reads on the primary for the read-your-writes window.
+func (u *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error, opts ...application.TxOption) error {
+	o := application.NewTxOptions(opts...)
+
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		if err := o.Nested(st.opts); err != nil {
+			return err
+		}
+		return u.savepoint(ctx, st, fn)
+	}
+
+	tx, err := u.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: isolationLevel(o.Isolation),
+		ReadOnly:  o.ReadOnly,
+	})
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
 
-	txCtx := context.WithValue(ctx, txKey{}, tx)
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	txCtx := context.WithValue(ctx, txKey{}, &txState{tx: tx.Tx, opts: o})
 
 	if err := fn(txCtx); err != nil {
 		_ = tx.Rollback()
@@ -33,9 +64,63 @@ func (u *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error)
 	return tx.Commit()
 }
 
-func executor(ctx context.Context, db *sqlx.DB) sqlxExecutor {
-	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok && tx != nil {
-		return tx
+// Active returns the options of the transaction carried by ctx, if any.
+func (u *TxManager) Active(ctx context.Context) (application.TxOptions, bool) {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return st.opts, true
+	}
+	return application.TxOptions{}, false
+}
+
+// savepoint runs fn inside the transaction st. The enclosing Do rolls the
+// whole transaction back on panic, so there is nothing to undo here.
+func (u *TxManager) savepoint(ctx context.Context, st *txState, fn func(ctx context.Context) error) error {
+	st.savepoints++
+	name := fmt.Sprintf("sp_%d", st.savepoints)
+
+	if _, err := st.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+
+	if err := fn(ctx); err != nil {
+		if _, rbErr := st.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return fmt.Errorf("rollback to savepoint: %w (after %w)", rbErr, err)
+		}
+		return err
+	}
+
+	if _, err := st.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+	return nil
+}
+
+func isolationLevel(level application.IsolationLevel) sql.IsolationLevel {
+	switch level {
+	case application.IsolationReadCommitted:
+		return sql.LevelReadCommitted
+	case application.IsolationRepeatableRead:
+		return sql.LevelRepeatableRead
+	case application.IsolationSerializable:
+		return sql.LevelSerializable
+	}
+	return sql.LevelDefault
+}
+
+// reader returns the executor for query-side reads. Outside a transaction it
+// is the pool picked by the router, which may lag behind the primary.
+func reader(ctx context.Context, router *psqlclient.Router) sqlxExecutor {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return st.tx
+	}
+	return router.Reader(ctx)
+}
+
+// executor returns the transaction in ctx or, outside one, the primary, which
+// reports every statement that writes to the router.
+func executor(ctx context.Context, db *psqlclient.Writer) sqlxExecutor {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return st.tx
 	}
 	return db
 }
@@ -0,0 +1,21 @@
+package sqlite
+
+import (
+	"context"
+	"slices"
+)
+
+// insertBatchSize keeps multi-row inserts below the limit of 32766 bind
+// parameters per statement.
+const insertBatchSize = 1000
+
+// insertBatches runs the named insert for rows in batches. sqlx expands the
+// VALUES clause of the query into one row per element of a batch.
+func insertBatches[T any](ctx context.Context, exec sqlxExecutor, query string, rows []T) error {
+	for batch := range slices.Chunk(rows, insertBatchSize) {
+		if _, err := exec.NamedExecContext(ctx, query, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/migrations"
+	"github.com/wintermonth2298/library-ddd/internal/pkg/sqliteclient"
+)
+
+// openTestDB creates a migrated database file that is removed after the
+// test.
+func openTestDB(tb testing.TB) *sqlx.DB {
+	tb.Helper()
+
+	path := filepath.Join(tb.TempDir(), "catalog.db")
+	db, err := sqliteclient.New(context.Background(), sqliteclient.Config{Path: path})
+	if err != nil {
+		tb.Fatalf("open test db: %v", err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+
+	goose.SetBaseFS(migrations.SQLiteFS)
+	goose.SetLogger(goose.NopLogger())
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		tb.Fatal(err)
+	}
+	if err := goose.Up(db.DB, "sqlite"); err != nil {
+		tb.Fatalf("migrate test db: %v", err)
+	}
+
+	return db
+}
+
+func newTestStorage(tb testing.TB) *Storage {
+	return NewStorage(openTestDB(tb), slog.New(slog.DiscardHandler))
+}
@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+// constraintErrors maps schema constraints to the domain errors they guard
+// against. SQLite reports unique violations by column and check violations
+// by constraint name. Foreign key violations carry neither and are returned
+// as is.
+var constraintErrors = map[string]error{
+	"slides.barcode":                     domain.ErrSlideBarcodeTaken,
+	"slides_stain_type_check":            domain.ErrInvalidSlideStain,
+	"slides_level_check":                 domain.ErrInvalidSlideLevel,
+	"slides_preparation_status_check":    domain.ErrInvalidSlideTransition,
+	"cases_status_check":                 domain.ErrInvalidCaseTransition,
+	"cases_priority_check":               domain.ErrInvalidCasePriority,
+	"case_assignments_pathologist_check": domain.ErrPathologistRequired,
+}
+
+// translateError replaces constraint violations with domain errors and keeps
+// the original error otherwise.
+func translateError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.Code != sqlite3.ErrConstraint {
+		return err
+	}
+
+	_, constraint, _ := strings.Cut(sqliteErr.Error(), "constraint failed: ")
+	if domainErr, ok := constraintErrors[constraint]; ok {
+		return domainErr
+	}
+	return err
+}
@@ -0,0 +1,201 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/mapping"
+)
+
+type EventsStorage struct {
+	db *sqlx.DB
+}
+
+func NewEventsStorage(db *sqlx.DB) *EventsStorage {
+	return &EventsStorage{db: db}
+}
+
+func (s *EventsStorage) MarkPublished(ctx context.Context, events []domain.Event) error {
+	exec := executor(ctx, s.db)
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.EventID().String())
+	}
+
+	query, args, err := sqlx.In(`UPDATE events SET published = 1 WHERE id IN (?)`, ids)
+	if err != nil {
+		return fmt.Errorf("prepare mark published: %w", err)
+	}
+
+	_, err = exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("mark published: %w", err)
+	}
+	return nil
+}
+
+func (s *EventsStorage) FetchUnpublished(ctx context.Context, limit int) ([]domain.Event, error) {
+	exec := executor(ctx, s.db)
+
+	const query = `
+		SELECT id, type, created_at, published, payload
+		FROM events
+		WHERE NOT published
+		ORDER BY created_at, id
+		LIMIT ?
+	`
+
+	var events []mapping.EventModel
+	err := exec.SelectContext(ctx, &events, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("fetch outbox: %w", err)
+	}
+
+	return toDomainEvents(events)
+}
+
+// Fetch returns events, archived ones included, ordered by creation time and
+// ID, starting after the given position.
+func (s *EventsStorage) Fetch(ctx context.Context, after time.Time, afterID uuid.UUID, limit int) ([]domain.Event, error) {
+	exec := executor(ctx, s.db)
+
+	const query = `
+		SELECT id, type, created_at, published, payload
+		FROM (
+			SELECT id, type, created_at, published, payload
+			FROM events
+			UNION ALL
+			SELECT id, type, created_at, 1 AS published, payload
+			FROM events_archive
+		) e
+		WHERE (created_at, id) > (?, ?)
+		ORDER BY created_at, id
+		LIMIT ?
+	`
+
+	var events []mapping.EventModel
+	if err := exec.SelectContext(ctx, &events, query, utc(after), afterID.String(), limit); err != nil {
+		return nil, fmt.Errorf("fetch events: %w", err)
+	}
+
+	return toDomainEvents(events)
+}
+
+// Add stores the events with multi-row inserts, so bulk operations do not
+// pay a round trip per event.
+func (s *EventsStorage) Add(ctx context.Context, events []domain.Event) error {
+	exec := executor(ctx, s.db)
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	eventModels := make([]mapping.EventModel, 0, len(events))
+	for _, event := range events {
+		e, err := mapping.ToModelEvent(event, false)
+		if err != nil {
+			return fmt.Errorf("map domain->model: %w", err)
+		}
+		e.CreatedAt = utc(e.CreatedAt)
+		eventModels = append(eventModels, e)
+	}
+
+	const query = `
+		INSERT INTO events (id, type, created_at, published, payload)
+		VALUES (:id, :type, :created_at, :published, :payload)
+	`
+
+	if err := insertBatches(ctx, exec, query, eventModels); err != nil {
+		return fmt.Errorf("insert outbox events: %w", err)
+	}
+
+	return nil
+}
+
+// Archive moves up to limit published events created before the given time
+// into events_archive. Events created after the oldest unpublished one are
+// kept, since handlers that lag behind may still need them.
+func (s *EventsStorage) Archive(ctx context.Context, before time.Time, limit int) (int, error) {
+	var archived int
+
+	err := inTx(ctx, s.db, func(ctx context.Context) error {
+		exec := executor(ctx, s.db)
+
+		var ids []string
+		err := exec.SelectContext(ctx, &ids, `
+			SELECT id
+			FROM events
+			WHERE published
+			  AND created_at < ?
+			  AND created_at < COALESCE((SELECT MIN(created_at) FROM events WHERE NOT published), ?)
+			ORDER BY created_at, id
+			LIMIT ?
+		`, utc(before), utc(before), limit)
+		if err != nil {
+			return fmt.Errorf("select events to archive: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		queries := []string{
+			`INSERT INTO events_archive (id, type, created_at, payload, archived_at)
+			SELECT id, type, created_at, payload, CURRENT_TIMESTAMP
+			FROM events
+			WHERE id IN (?)`,
+			`DELETE FROM events WHERE id IN (?)`,
+		}
+		for _, tmpl := range queries {
+			query, args, err := sqlx.In(tmpl, ids)
+			if err != nil {
+				return fmt.Errorf("prepare archive events: %w", err)
+			}
+			if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("archive events: %w", err)
+			}
+		}
+
+		archived = len(ids)
+		return nil
+	})
+
+	return archived, err
+}
+
+func (s *EventsStorage) PruneArchive(ctx context.Context, before time.Time) (int, error) {
+	exec := executor(ctx, s.db)
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM events_archive WHERE created_at < ?`, utc(before))
+	if err != nil {
+		return 0, fmt.Errorf("prune archived events: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("check rows affected: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+func toDomainEvents(events []mapping.EventModel) ([]domain.Event, error) {
+	domainEvents := make([]domain.Event, 0, len(events))
+	for _, e := range events {
+		e, err := mapping.ToDomainEvent(e)
+		if err != nil {
+			return nil, fmt.Errorf("map model->domain: %w", err)
+		}
+		domainEvents = append(domainEvents, e)
+	}
+
+	return domainEvents, nil
+}
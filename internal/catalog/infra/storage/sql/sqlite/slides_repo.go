@@ -0,0 +1,399 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/mapping"
+)
+
+type SlidesRepo struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewSlidesRepo(db *sqlx.DB, logger *slog.Logger) *SlidesRepo {
+	return &SlidesRepo{db: db, logger: logger}
+}
+
+func (r *SlidesRepo) GetSlidesByCaseID(ctx context.Context, caseID uuid.UUID) ([]domain.Slide, error) {
+	exec := executor(ctx, r.db)
+
+	query := `
+		SELECT id, version, preparation_status, case_id,
+			stain_type, stain_name, block_id, level, barcode, label,
+			created_at, started_at, finished_at, failed_at, failure_reason,
+			assigned_to, lease_expires_at, deleted_at
+		FROM slides
+		WHERE case_id = ? AND deleted_at IS NULL
+		  AND case_id IN (SELECT id FROM cases WHERE deleted_at IS NULL)
+	`
+
+	var models []mapping.SlideModel
+	err := exec.SelectContext(ctx, &models, query, caseID.String())
+	if err != nil {
+		return nil, fmt.Errorf("select slides by caseID: %w", err)
+	}
+
+	slides := make([]domain.Slide, 0, len(models))
+	for _, model := range models {
+		slides = append(slides, mapping.ToDomainSlide(model))
+	}
+
+	return slides, nil
+}
+
+func (r *SlidesRepo) GetProcessingSlidesStartedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error) {
+	exec := executor(ctx, r.db)
+
+	query := `
+		SELECT id, version, preparation_status, case_id,
+			stain_type, stain_name, block_id, level, barcode, label,
+			created_at, started_at, finished_at, failed_at, failure_reason,
+			assigned_to, lease_expires_at, deleted_at
+		FROM slides
+		WHERE preparation_status = ?
+		  AND started_at < ?
+		  AND deleted_at IS NULL
+		  AND case_id IN (SELECT id FROM cases WHERE deleted_at IS NULL)
+		ORDER BY started_at
+	`
+
+	var models []mapping.SlideModel
+	err := exec.SelectContext(ctx, &models, query,
+		mapping.ToModelSlidePreparationStatus(domain.SlidePreparationStatusProcessing),
+		utc(before),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select processing slides: %w", err)
+	}
+
+	slides := make([]domain.Slide, 0, len(models))
+	for _, model := range models {
+		slides = append(slides, mapping.ToDomainSlide(model))
+	}
+
+	return slides, nil
+}
+
+// GetNextQueuedSlide returns the not started slide of an open case that
+// should be processed next: highest case priority first, then the oldest
+// slide. Claims do not race, since write transactions are serialized.
+func (r *SlidesRepo) GetNextQueuedSlide(ctx context.Context) (domain.Slide, error) {
+	exec := executor(ctx, r.db)
+
+	var model mapping.SlideModel
+	err := exec.GetContext(ctx, &model, `
+		SELECT s.id, s.version, s.preparation_status, s.case_id,
+			s.stain_type, s.stain_name, s.block_id, s.level, s.barcode, s.label,
+			s.created_at, s.started_at, s.finished_at, s.failed_at, s.failure_reason,
+			s.assigned_to, s.lease_expires_at, s.deleted_at
+		FROM slides s
+		JOIN cases c ON c.id = s.case_id
+		WHERE s.preparation_status = ?
+		  AND c.status = ?
+		  AND s.deleted_at IS NULL
+		  AND c.deleted_at IS NULL
+		ORDER BY c.priority DESC, s.created_at ASC NULLS FIRST, s.id
+		LIMIT 1
+	`,
+		mapping.ToModelSlidePreparationStatus(domain.SlidePreparationStatusNotStarted),
+		mapping.ToModelCaseStatus(domain.CaseStatusOpen),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Slide{}, domain.ErrNoQueuedSlides
+		}
+		return domain.Slide{}, fmt.Errorf("select next queued slide: %w", err)
+	}
+
+	return mapping.ToDomainSlide(model), nil
+}
+
+func (r *SlidesRepo) GetSlidesWithLeaseExpiredBefore(ctx context.Context, before time.Time) ([]domain.Slide, error) {
+	exec := executor(ctx, r.db)
+
+	query := `
+		SELECT id, version, preparation_status, case_id,
+			stain_type, stain_name, block_id, level, barcode, label,
+			created_at, started_at, finished_at, failed_at, failure_reason,
+			assigned_to, lease_expires_at, deleted_at
+		FROM slides
+		WHERE preparation_status = ?
+		  AND lease_expires_at < ?
+		  AND deleted_at IS NULL
+		  AND case_id IN (SELECT id FROM cases WHERE deleted_at IS NULL)
+		ORDER BY lease_expires_at
+	`
+
+	var models []mapping.SlideModel
+	err := exec.SelectContext(ctx, &models, query,
+		mapping.ToModelSlidePreparationStatus(domain.SlidePreparationStatusProcessing),
+		utc(before),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select slides with expired lease: %w", err)
+	}
+
+	slides := make([]domain.Slide, 0, len(models))
+	for _, model := range models {
+		slides = append(slides, mapping.ToDomainSlide(model))
+	}
+
+	return slides, nil
+}
+
+func (r *SlidesRepo) GetSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error) {
+	exec := executor(ctx, r.db)
+
+	var model mapping.SlideModel
+	err := exec.GetContext(ctx, &model, `
+		SELECT id, version, preparation_status, case_id,
+			stain_type, stain_name, block_id, level, barcode, label,
+			created_at, started_at, finished_at, failed_at, failure_reason,
+			assigned_to, lease_expires_at, deleted_at
+		FROM slides
+		WHERE id = ? AND deleted_at IS NULL
+		  AND case_id IN (SELECT id FROM cases WHERE deleted_at IS NULL)
+	`, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Slide{}, domain.ErrSlideNotFound
+		}
+		return domain.Slide{}, fmt.Errorf("select slide: %w", err)
+	}
+
+	return mapping.ToDomainSlide(model), nil
+}
+
+func (r *SlidesRepo) GetSlideByBarcode(ctx context.Context, barcode string) (domain.Slide, error) {
+	exec := executor(ctx, r.db)
+
+	var model mapping.SlideModel
+	err := exec.GetContext(ctx, &model, `
+		SELECT id, version, preparation_status, case_id,
+			stain_type, stain_name, block_id, level, barcode, label,
+			created_at, started_at, finished_at, failed_at, failure_reason,
+			assigned_to, lease_expires_at, deleted_at
+		FROM slides
+		WHERE barcode = ? AND deleted_at IS NULL
+		  AND case_id IN (SELECT id FROM cases WHERE deleted_at IS NULL)
+	`, barcode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Slide{}, domain.ErrSlideNotFound
+		}
+		return domain.Slide{}, fmt.Errorf("select slide by barcode: %w", err)
+	}
+
+	return mapping.ToDomainSlide(model), nil
+}
+
+func (r *SlidesRepo) GetSlidesByBarcodes(ctx context.Context, barcodes []string) ([]domain.Slide, error) {
+	exec := executor(ctx, r.db)
+
+	if len(barcodes) == 0 {
+		return nil, nil
+	}
+
+	const tmpl = `
+		SELECT id, version, preparation_status, case_id,
+			stain_type, stain_name, block_id, level, barcode, label,
+			created_at, started_at, finished_at, failed_at, failure_reason,
+			assigned_to, lease_expires_at, deleted_at
+		FROM slides
+		WHERE barcode IN (?) AND deleted_at IS NULL
+		  AND case_id IN (SELECT id FROM cases WHERE deleted_at IS NULL)
+	`
+
+	query, args, err := sqlx.In(tmpl, barcodes)
+	if err != nil {
+		return nil, fmt.Errorf("prepare select slides by barcodes: %w", err)
+	}
+	query = r.db.Rebind(query)
+
+	var models []mapping.SlideModel
+	if err := exec.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, fmt.Errorf("select slides by barcodes: %w", err)
+	}
+
+	slides := make([]domain.Slide, 0, len(models))
+	for _, model := range models {
+		slides = append(slides, mapping.ToDomainSlide(model))
+	}
+
+	return slides, nil
+}
+
+func (r *SlidesRepo) GetDeletedSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error) {
+	exec := executor(ctx, r.db)
+
+	var model mapping.SlideModel
+	err := exec.GetContext(ctx, &model, `
+		SELECT id, version, preparation_status, case_id,
+			stain_type, stain_name, block_id, level, barcode, label,
+			created_at, started_at, finished_at, failed_at, failure_reason,
+			assigned_to, lease_expires_at, deleted_at
+		FROM slides
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Slide{}, domain.ErrSlideNotFound
+		}
+		return domain.Slide{}, fmt.Errorf("select deleted slide: %w", err)
+	}
+
+	return mapping.ToDomainSlide(model), nil
+}
+
+func (r *SlidesRepo) GetSlidesDeletedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error) {
+	exec := executor(ctx, r.db)
+
+	query := `
+		SELECT id, version, preparation_status, case_id,
+			stain_type, stain_name, block_id, level, barcode, label,
+			created_at, started_at, finished_at, failed_at, failure_reason,
+			assigned_to, lease_expires_at, deleted_at
+		FROM slides
+		WHERE deleted_at < ?
+		ORDER BY deleted_at
+	`
+
+	var models []mapping.SlideModel
+	if err := exec.SelectContext(ctx, &models, query, utc(before)); err != nil {
+		return nil, fmt.Errorf("select deleted slides: %w", err)
+	}
+
+	slides := make([]domain.Slide, 0, len(models))
+	for _, model := range models {
+		slides = append(slides, mapping.ToDomainSlide(model))
+	}
+
+	return slides, nil
+}
+
+// DeleteSlide removes the slide row for good. Soft deletion goes through
+// SaveSlide like any other change.
+func (r *SlidesRepo) DeleteSlide(ctx context.Context, s domain.Slide) error {
+	exec := executor(ctx, r.db)
+
+	result, err := exec.ExecContext(ctx, `DELETE FROM slides WHERE id = ? AND version = ?`, s.ID.String(), int(s.Version))
+	if err != nil {
+		return fmt.Errorf("delete slide: %w", translateError(err))
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check rows affected: %w", err)
+	}
+	if rows == 0 {
+		r.logger.WarnContext(ctx, "slide version conflict", "slide_id", s.ID, "case_id", s.CaseID, "version", s.Version)
+		return domain.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// SaveSlides inserts new slides with multi-row inserts. Slides that were
+// already stored have to be saved one by one with SaveSlide.
+func (r *SlidesRepo) SaveSlides(ctx context.Context, slides []domain.Slide) error {
+	exec := executor(ctx, r.db)
+
+	models := make([]mapping.SlideModel, 0, len(slides))
+	for _, s := range slides {
+		if s.Version != 0 {
+			return fmt.Errorf("save slides: slide %s is already stored", s.ID)
+		}
+		models = append(models, utcSlide(mapping.ToModelSlide(s)))
+	}
+
+	const insertQuery = `
+		INSERT INTO slides (
+			id, version, preparation_status, case_id,
+			stain_type, stain_name, block_id, level, barcode, label,
+			created_at, started_at, finished_at, failed_at, failure_reason,
+			assigned_to, lease_expires_at, deleted_at
+		)
+		VALUES (
+			:id, 1, :preparation_status, :case_id,
+			:stain_type, :stain_name, :block_id, :level, :barcode, :label,
+			:created_at, :started_at, :finished_at, :failed_at, :failure_reason,
+			:assigned_to, :lease_expires_at, :deleted_at
+		)
+	`
+
+	if err := insertBatches(ctx, exec, insertQuery, models); err != nil {
+		return fmt.Errorf("insert slides: %w", translateError(err))
+	}
+
+	return nil
+}
+
+func (r *SlidesRepo) SaveSlide(ctx context.Context, s domain.Slide) error {
+	exec := executor(ctx, r.db)
+
+	model := utcSlide(mapping.ToModelSlide(s))
+
+	if s.Version == 0 {
+		insertQuery := `
+			INSERT INTO slides (
+				id, version, preparation_status, case_id,
+				stain_type, stain_name, block_id, level, barcode, label,
+				created_at, started_at, finished_at, failed_at, failure_reason,
+				assigned_to, lease_expires_at, deleted_at
+			)
+			VALUES (
+				:id, 1, :preparation_status, :case_id,
+				:stain_type, :stain_name, :block_id, :level, :barcode, :label,
+				:created_at, :started_at, :finished_at, :failed_at, :failure_reason,
+				:assigned_to, :lease_expires_at, :deleted_at
+			)
+		`
+		_, err := exec.NamedExecContext(ctx, insertQuery, model)
+		if err != nil {
+			return fmt.Errorf("insert slide: %w", translateError(err))
+		}
+		return nil
+	}
+
+	updateQuery := `
+		UPDATE slides
+		SET version = version + 1,
+			case_id = :case_id,
+			block_id = :block_id,
+			label = :label,
+			preparation_status = :preparation_status,
+			started_at = :started_at,
+			finished_at = :finished_at,
+			failed_at = :failed_at,
+			failure_reason = :failure_reason,
+			assigned_to = :assigned_to,
+			lease_expires_at = :lease_expires_at,
+			deleted_at = :deleted_at
+		WHERE id = :id AND version = :version
+	`
+
+	result, err := exec.NamedExecContext(ctx, updateQuery, model)
+	if err != nil {
+		return fmt.Errorf("update slide: %w", translateError(err))
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check rows affected: %w", err)
+	}
+	if rows == 0 {
+		r.logger.WarnContext(ctx, "slide version conflict", "slide_id", s.ID, "case_id", s.CaseID, "version", s.Version)
+		return domain.ErrVersionConflict
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+)
+
+type Storage struct {
+	casesRepo     *CasesRepo
+	slidesRepo    *SlidesRepo
+	eventsStorage *EventsStorage
+	txManager     *TxManager
+}
+
+func NewStorage(db *sqlx.DB, logger *slog.Logger) *Storage {
+	return &Storage{
+		casesRepo:     NewCasesRepo(db, logger),
+		slidesRepo:    NewSlidesRepo(db, logger),
+		eventsStorage: NewEventsStorage(db),
+		txManager:     NewTxManager(db),
+	}
+}
+
+func (s *Storage) GetCase(ctx context.Context, caseID uuid.UUID) (domain.Case, error) {
+	return s.casesRepo.GetCase(ctx, caseID)
+}
+
+func (s *Storage) SaveCase(ctx context.Context, c domain.Case) error {
+	return s.casesRepo.SaveCase(ctx, c)
+}
+
+func (s *Storage) GetDeletedCase(ctx context.Context, caseID uuid.UUID) (domain.Case, error) {
+	return s.casesRepo.GetDeletedCase(ctx, caseID)
+}
+
+func (s *Storage) GetCaseIDsDeletedBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	return s.casesRepo.GetCaseIDsDeletedBefore(ctx, before)
+}
+
+func (s *Storage) DeleteCase(ctx context.Context, c domain.Case) error {
+	return s.casesRepo.DeleteCase(ctx, c)
+}
+
+func (s *Storage) GetSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error) {
+	return s.slidesRepo.GetSlide(ctx, id)
+}
+
+func (s *Storage) GetSlideByBarcode(ctx context.Context, barcode string) (domain.Slide, error) {
+	return s.slidesRepo.GetSlideByBarcode(ctx, barcode)
+}
+
+func (s *Storage) SaveSlide(ctx context.Context, slide domain.Slide) error {
+	return s.slidesRepo.SaveSlide(ctx, slide)
+}
+
+func (s *Storage) GetSlidesByBarcodes(ctx context.Context, barcodes []string) ([]domain.Slide, error) {
+	return s.slidesRepo.GetSlidesByBarcodes(ctx, barcodes)
+}
+
+func (s *Storage) SaveSlides(ctx context.Context, slides []domain.Slide) error {
+	return s.slidesRepo.SaveSlides(ctx, slides)
+}
+
+func (s *Storage) AddEvent(ctx context.Context, events []domain.Event) error {
+	return s.eventsStorage.Add(ctx, events)
+}
+
+func (s *Storage) MarkEventPublished(ctx context.Context, events []domain.Event) error {
+	return s.eventsStorage.MarkPublished(ctx, events)
+}
+
+func (s *Storage) FetchUnpublishedEvents(ctx context.Context, limit int) ([]domain.Event, error) {
+	return s.eventsStorage.FetchUnpublished(ctx, limit)
+}
+
+func (s *Storage) FetchEvents(ctx context.Context, after time.Time, afterID uuid.UUID, limit int) ([]domain.Event, error) {
+	return s.eventsStorage.Fetch(ctx, after, afterID, limit)
+}
+
+func (s *Storage) ArchiveEvents(ctx context.Context, before time.Time, limit int) (int, error) {
+	return s.eventsStorage.Archive(ctx, before, limit)
+}
+
+func (s *Storage) PruneArchivedEvents(ctx context.Context, before time.Time) (int, error) {
+	return s.eventsStorage.PruneArchive(ctx, before)
+}
+
+func (s *Storage) GetSlidesByCaseID(ctx context.Context, caseID uuid.UUID) ([]domain.Slide, error) {
+	return s.slidesRepo.GetSlidesByCaseID(ctx, caseID)
+}
+
+func (s *Storage) GetProcessingSlidesStartedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error) {
+	return s.slidesRepo.GetProcessingSlidesStartedBefore(ctx, before)
+}
+
+func (s *Storage) GetNextQueuedSlide(ctx context.Context) (domain.Slide, error) {
+	return s.slidesRepo.GetNextQueuedSlide(ctx)
+}
+
+func (s *Storage) GetSlidesWithLeaseExpiredBefore(ctx context.Context, before time.Time) ([]domain.Slide, error) {
+	return s.slidesRepo.GetSlidesWithLeaseExpiredBefore(ctx, before)
+}
+
+func (s *Storage) GetDeletedSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error) {
+	return s.slidesRepo.GetDeletedSlide(ctx, id)
+}
+
+func (s *Storage) GetSlidesDeletedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error) {
+	return s.slidesRepo.GetSlidesDeletedBefore(ctx, before)
+}
+
+func (s *Storage) DeleteSlide(ctx context.Context, slide domain.Slide) error {
+	return s.slidesRepo.DeleteSlide(ctx, slide)
+}
+
+func (s *Storage) WithTx(ctx context.Context, fn func(ctx context.Context) error, opts ...application.TxOption) error {
+	return s.txManager.Do(ctx, fn, opts...)
+}
+
+func (s *Storage) ActiveTx(ctx context.Context) (application.TxOptions, bool) {
+	return s.txManager.Active(ctx)
+}
@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/wintermonth2298/library-ddd/internal/catalog/infra/storage/sql/mapping"
+)
+
+// Timestamps are stored as text, so they are kept in UTC for comparisons to
+// follow the time order.
+
+func utc(t time.Time) time.Time {
+	return t.UTC()
+}
+
+func utcNull(t sql.NullTime) sql.NullTime {
+	t.Time = t.Time.UTC()
+	return t
+}
+
+func utcSlide(m mapping.SlideModel) mapping.SlideModel {
+	m.CreatedAt = utcNull(m.CreatedAt)
+	m.StartedAt = utcNull(m.StartedAt)
+	m.FinishedAt = utcNull(m.FinishedAt)
+	m.FailedAt = utcNull(m.FailedAt)
+	m.LeaseExpiresAt = utcNull(m.LeaseExpiresAt)
+	m.DeletedAt = utcNull(m.DeletedAt)
+	return m
+}
+
+func utcCase(m mapping.CaseModel) mapping.CaseModel {
+	m.DeletedAt = utcNull(m.DeletedAt)
+	return m
+}
@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+)
+
+type txKey struct{}
+
+type txState struct {
+	tx         *sqlx.Tx
+	opts       application.TxOptions
+	savepoints int
+}
+
+type TxManager struct {
+	db *sqlx.DB
+}
+
+func NewTxManager(db *sqlx.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// Do runs fn in a transaction, or behind a savepoint of the one carried by
+// ctx. SQLite transactions are always serializable, so the isolation level
+// is not passed on; read-only ones are enforced with the query_only pragma.
+func (u *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error, opts ...application.TxOption) error {
+	o := application.NewTxOptions(opts...)
+
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		if err := o.Nested(st.opts); err != nil {
+			return err
+		}
+		return savepoint(ctx, st, fn)
+	}
+
+	return begin(ctx, u.db, o, fn)
+}
+
+// Active returns the options of the transaction carried by ctx, if any.
+func (u *TxManager) Active(ctx context.Context) (application.TxOptions, bool) {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return st.opts, true
+	}
+	return application.TxOptions{}, false
+}
+
+// inTx runs fn in the transaction carried by ctx or in a new one. SQLite
+// allows a single writer, so nested transactions would wait on each other.
+func inTx(ctx context.Context, db *sqlx.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+	return begin(ctx, db, application.TxOptions{}, fn)
+}
+
+func begin(ctx context.Context, db *sqlx.DB, o application.TxOptions, fn func(ctx context.Context) error) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if o.ReadOnly {
+		if _, err := tx.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("set read-only: %w", err)
+		}
+	}
+
+	// finish switches query_only off again, since it belongs to the
+	// connection and would stick to it once it is back in the pool.
+	finish := func(commit bool) error {
+		if o.ReadOnly {
+			if _, err := tx.ExecContext(context.WithoutCancel(ctx), "PRAGMA query_only = OFF"); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("reset read-only: %w", err)
+			}
+		}
+		if !commit {
+			return tx.Rollback()
+		}
+		return tx.Commit()
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = finish(false)
+			panic(p)
+		}
+	}()
+
+	txCtx := context.WithValue(ctx, txKey{}, &txState{tx: tx, opts: o})
+
+	if err := fn(txCtx); err != nil {
+		_ = finish(false)
+		return err
+	}
+	return finish(true)
+}
+
+func savepoint(ctx context.Context, st *txState, fn func(ctx context.Context) error) error {
+	st.savepoints++
+	name := fmt.Sprintf("sp_%d", st.savepoints)
+
+	if _, err := st.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+
+	if err := fn(ctx); err != nil {
+		if _, rbErr := st.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return fmt.Errorf("rollback to savepoint: %w (after %w)", rbErr, err)
+		}
+		return err
+	}
+
+	if _, err := st.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+	return nil
+}
+
+func executor(ctx context.Context, db *sqlx.DB) sqlxExecutor {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return st.tx
+	}
+	return db
+}
+
+type sqlxExecutor interface {
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
@@ -0,0 +1,793 @@
+// Package storagetest checks that a storage adapter behaves the way the
+// application expects, so every adapter can run the same tests.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/application"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain"
+	"github.com/wintermonth2298/library-ddd/internal/catalog/domain/domaintest"
+)
+
+// Storage is the method set of the storage the application runs on.
+type Storage interface {
+	GetCase(ctx context.Context, caseID uuid.UUID) (domain.Case, error)
+	SaveCase(ctx context.Context, c domain.Case) error
+	GetDeletedCase(ctx context.Context, caseID uuid.UUID) (domain.Case, error)
+	GetCaseIDsDeletedBefore(ctx context.Context, before time.Time) ([]uuid.UUID, error)
+	DeleteCase(ctx context.Context, c domain.Case) error
+
+	GetSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error)
+	GetSlideByBarcode(ctx context.Context, barcode string) (domain.Slide, error)
+	GetSlidesByBarcodes(ctx context.Context, barcodes []string) ([]domain.Slide, error)
+	GetSlidesByCaseID(ctx context.Context, caseID uuid.UUID) ([]domain.Slide, error)
+	GetDeletedSlide(ctx context.Context, id uuid.UUID) (domain.Slide, error)
+	GetSlidesDeletedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error)
+	GetNextQueuedSlide(ctx context.Context) (domain.Slide, error)
+	GetProcessingSlidesStartedBefore(ctx context.Context, before time.Time) ([]domain.Slide, error)
+	GetSlidesWithLeaseExpiredBefore(ctx context.Context, before time.Time) ([]domain.Slide, error)
+	SaveSlide(ctx context.Context, slide domain.Slide) error
+	SaveSlides(ctx context.Context, slides []domain.Slide) error
+	DeleteSlide(ctx context.Context, slide domain.Slide) error
+
+	AddEvent(ctx context.Context, events []domain.Event) error
+	MarkEventPublished(ctx context.Context, events []domain.Event) error
+	FetchUnpublishedEvents(ctx context.Context, limit int) ([]domain.Event, error)
+	FetchEvents(ctx context.Context, after time.Time, afterID uuid.UUID, limit int) ([]domain.Event, error)
+	ArchiveEvents(ctx context.Context, before time.Time, limit int) (int, error)
+	PruneArchivedEvents(ctx context.Context, before time.Time) (int, error)
+
+	WithTx(ctx context.Context, fn func(ctx context.Context) error, opts ...application.TxOption) error
+	ActiveTx(ctx context.Context) (application.TxOptions, bool)
+}
+
+// start is whole seconds in UTC, so timestamps survive every database
+// round trip unchanged.
+var start = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+// Run runs the contract against storages made by newStorage. Every subtest
+// gets a fresh, empty storage.
+func Run(t *testing.T, newStorage func(t *testing.T) Storage) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, f *fixture)
+	}{
+		{"SaveAndGetCase", testSaveAndGetCase},
+		{"CaseVersionConflict", testCaseVersionConflict},
+		{"SaveAndGetSlides", testSaveAndGetSlides},
+		{"SlideVersionConflict", testSlideVersionConflict},
+		{"BarcodeLookup", testBarcodeLookup},
+		{"WorkQueue", testWorkQueue},
+		{"SoftDeleteSlide", testSoftDeleteSlide},
+		{"SoftDeleteCase", testSoftDeleteCase},
+		{"DeleteMergeTarget", testDeleteMergeTarget},
+		{"Outbox", testOutbox},
+		{"TxRollback", testTxRollback},
+		{"NestedTx", testNestedTx},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, clock := domaintest.NewEnv(start)
+			tt.test(t, &fixture{
+				s:       newStorage(t),
+				env:     env,
+				clock:   clock,
+				service: domain.NewService(env),
+			})
+		})
+	}
+}
+
+type fixture struct {
+	s       Storage
+	env     domain.Env
+	clock   *domaintest.Clock
+	service *domain.Service
+}
+
+// saveCase stores a new routine case with one specimen and one block.
+func (f *fixture) saveCase(t *testing.T) (domain.Case, domain.Block) {
+	t.Helper()
+	return f.saveCaseWithPriority(t, domain.CasePriorityRoutine)
+}
+
+func (f *fixture) saveCaseWithPriority(t *testing.T, priority domain.CasePriority) (domain.Case, domain.Block) {
+	t.Helper()
+
+	c, err := domain.CreateCase(f.env, priority)
+	if err != nil {
+		t.Fatal(err)
+	}
+	specimen, err := c.AddSpecimen(f.env, "skin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := c.AddBlock(f.env, specimen.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.PullEvents()
+
+	if err := f.s.SaveCase(context.Background(), c); err != nil {
+		t.Fatalf("save case: %v", err)
+	}
+	return f.getCase(t, c.ID), block
+}
+
+// saveSlides stores one HE slide per barcode in the case.
+func (f *fixture) saveSlides(t *testing.T, c domain.Case, block domain.Block, barcodes ...string) []domain.Slide {
+	t.Helper()
+	ctx := context.Background()
+
+	specs := make([]domain.SlideSpec, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		specs = append(specs, domain.SlideSpec{
+			Stain:   domain.Stain{Type: domain.StainTypeHE},
+			BlockID: block.ID,
+			Level:   1,
+			Barcode: barcode,
+		})
+	}
+
+	caseSlides, err := f.s.GetSlidesByCaseID(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("get case slides: %v", err)
+	}
+	slides, err := f.service.CreateSlides(&c, caseSlides, specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveSlides(ctx, slides); err != nil {
+		t.Fatalf("save slides: %v", err)
+	}
+
+	saved := make([]domain.Slide, 0, len(slides))
+	for _, slide := range slides {
+		saved = append(saved, f.getSlide(t, slide.ID))
+	}
+	return saved
+}
+
+func (f *fixture) getCase(t *testing.T, id uuid.UUID) domain.Case {
+	t.Helper()
+
+	c, err := f.s.GetCase(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get case %s: %v", id, err)
+	}
+	return c
+}
+
+func (f *fixture) getSlide(t *testing.T, id uuid.UUID) domain.Slide {
+	t.Helper()
+
+	slide, err := f.s.GetSlide(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get slide %s: %v", id, err)
+	}
+	return slide
+}
+
+func testSaveAndGetCase(t *testing.T, f *fixture) {
+	ctx := context.Background()
+
+	want, err := domain.CreateCase(f.env, domain.CasePriorityUrgent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	specimen, err := want.AddSpecimen(f.env, "skin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := want.AddBlock(f.env, specimen.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveCase(ctx, want); err != nil {
+		t.Fatalf("save case: %v", err)
+	}
+
+	got := f.getCase(t, want.ID)
+	if got.Version != want.Version+1 {
+		t.Errorf("version = %d, want %d", got.Version, want.Version+1)
+	}
+	if got.Priority != want.Priority || got.Status != want.Status {
+		t.Errorf("priority, status = %v, %v, want %v, %v", got.Priority, got.Status, want.Priority, want.Status)
+	}
+	if len(got.Specimens) != 1 || got.Specimens[0].ID != specimen.ID || got.Specimens[0].Description != "skin" {
+		t.Fatalf("specimens = %+v, want the skin specimen %s", got.Specimens, specimen.ID)
+	}
+	if blocks := got.Specimens[0].Blocks; len(blocks) != 1 || blocks[0].ID != block.ID {
+		t.Errorf("blocks = %+v, want block %s", blocks, block.ID)
+	}
+
+	if _, err := f.s.GetCase(ctx, uuid.New()); !errors.Is(err, domain.ErrCaseNotFound) {
+		t.Errorf("get unknown case: err = %v, want %v", err, domain.ErrCaseNotFound)
+	}
+}
+
+func testCaseVersionConflict(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	c, _ := f.saveCase(t)
+
+	stale := f.getCase(t, c.ID)
+	if err := c.Hold(f.env, "waiting for consent"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveCase(ctx, c); err != nil {
+		t.Fatalf("save case: %v", err)
+	}
+	if got := f.getCase(t, c.ID); got.Version != c.Version+1 {
+		t.Errorf("version = %d, want %d", got.Version, c.Version+1)
+	}
+
+	if err := stale.Hold(f.env, "waiting for consent"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveCase(ctx, stale); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Errorf("save stale case: err = %v, want %v", err, domain.ErrVersionConflict)
+	}
+	err := f.s.WithTx(ctx, func(ctx context.Context) error {
+		return f.s.DeleteCase(ctx, stale)
+	})
+	if !errors.Is(err, domain.ErrVersionConflict) {
+		t.Errorf("delete stale case: err = %v, want %v", err, domain.ErrVersionConflict)
+	}
+}
+
+func testSaveAndGetSlides(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	c, block := f.saveCase(t)
+	other, otherBlock := f.saveCase(t)
+
+	slides := f.saveSlides(t, c, block, "S-1", "S-2")
+	f.saveSlides(t, other, otherBlock, "S-3")
+
+	for _, slide := range slides {
+		if slide.Version != 1 || slide.CaseID != c.ID || slide.BlockID != block.ID {
+			t.Errorf("slide %s: version, case, block = %d, %s, %s, want 1, %s, %s",
+				slide.ID, slide.Version, slide.CaseID, slide.BlockID, c.ID, block.ID)
+		}
+		if slide.PreparationStatus != domain.SlidePreparationStatusNotStarted {
+			t.Errorf("slide %s: status = %v, want not started", slide.ID, slide.PreparationStatus)
+		}
+		if slide.Stain.Type != domain.StainTypeHE || slide.Level != 1 {
+			t.Errorf("slide %s: stain, level = %v, %d, want HE, 1", slide.ID, slide.Stain.Type, slide.Level)
+		}
+		if !slide.CreatedAt.Equal(start) {
+			t.Errorf("slide %s: created at %v, want %v", slide.ID, slide.CreatedAt, start)
+		}
+	}
+
+	caseSlides, err := f.s.GetSlidesByCaseID(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := slideIDs(caseSlides), slideIDs(slides); !slices.Equal(got, want) {
+		t.Errorf("case slides = %v, want %v", got, want)
+	}
+
+	if _, err := f.s.GetSlide(ctx, uuid.New()); !errors.Is(err, domain.ErrSlideNotFound) {
+		t.Errorf("get unknown slide: err = %v, want %v", err, domain.ErrSlideNotFound)
+	}
+}
+
+func testSlideVersionConflict(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	c, block := f.saveCase(t)
+	slide := f.saveSlides(t, c, block, "S-1")[0]
+
+	stale := slide
+	slide, err := f.service.StartSlide(slide, []domain.Slide{slide})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveSlide(ctx, slide); err != nil {
+		t.Fatalf("save slide: %v", err)
+	}
+	if got := f.getSlide(t, slide.ID); got.Version != slide.Version+1 || got.PreparationStatus != domain.SlidePreparationStatusProcessing {
+		t.Errorf("version, status = %d, %v, want %d, processing", got.Version, got.PreparationStatus, slide.Version+1)
+	}
+
+	stale, err = f.service.StartSlide(stale, []domain.Slide{stale})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveSlide(ctx, stale); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Errorf("save stale slide: err = %v, want %v", err, domain.ErrVersionConflict)
+	}
+}
+
+func testBarcodeLookup(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	c, block := f.saveCase(t)
+	slides := f.saveSlides(t, c, block, "S-1", "S-2")
+
+	got, err := f.s.GetSlideByBarcode(ctx, "S-2")
+	if err != nil {
+		t.Fatalf("get slide by barcode: %v", err)
+	}
+	if got.ID != slides[1].ID {
+		t.Errorf("slide by barcode = %s, want %s", got.ID, slides[1].ID)
+	}
+	if _, err := f.s.GetSlideByBarcode(ctx, "S-9"); !errors.Is(err, domain.ErrSlideNotFound) {
+		t.Errorf("get unknown barcode: err = %v, want %v", err, domain.ErrSlideNotFound)
+	}
+
+	taken, err := f.s.GetSlidesByBarcodes(ctx, []string{"S-1", "S-2", "S-9"})
+	if err != nil {
+		t.Fatalf("get slides by barcodes: %v", err)
+	}
+	if got, want := slideIDs(taken), slideIDs(slides); !slices.Equal(got, want) {
+		t.Errorf("slides by barcodes = %v, want %v", got, want)
+	}
+
+	other, otherBlock := f.saveCase(t)
+	duplicate, err := f.service.CreateSlide(other, nil, domain.SlideSpec{
+		Stain:   domain.Stain{Type: domain.StainTypeHE},
+		BlockID: otherBlock.ID,
+		Level:   1,
+		Barcode: "S-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveSlide(ctx, duplicate); !errors.Is(err, domain.ErrSlideBarcodeTaken) {
+		t.Errorf("save duplicate barcode: err = %v, want %v", err, domain.ErrSlideBarcodeTaken)
+	}
+}
+
+func testWorkQueue(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	if _, err := f.s.GetNextQueuedSlide(ctx); !errors.Is(err, domain.ErrNoQueuedSlides) {
+		t.Errorf("next queued slide of empty queue: err = %v, want %v", err, domain.ErrNoQueuedSlides)
+	}
+
+	routine, routineBlock := f.saveCase(t)
+	older := f.saveSlides(t, routine, routineBlock, "S-1")[0]
+	f.clock.Advance(time.Minute)
+	newer := f.saveSlides(t, routine, routineBlock, "S-2")[0]
+	f.clock.Advance(time.Minute)
+	urgent, urgentBlock := f.saveCaseWithPriority(t, domain.CasePriorityUrgent)
+	first := f.saveSlides(t, urgent, urgentBlock, "S-3")[0]
+
+	// Higher priority wins over age, then the oldest slide goes first.
+	for _, want := range []domain.Slide{first, older, newer} {
+		next, err := f.s.GetNextQueuedSlide(ctx)
+		if err != nil {
+			t.Fatalf("next queued slide: %v", err)
+		}
+		if next.ID != want.ID {
+			t.Fatalf("next queued slide = %s, want %s", next.ID, want.ID)
+		}
+
+		claimed, err := f.service.ClaimSlide(next, []domain.Slide{next}, "tech-1", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.s.SaveSlide(ctx, claimed); err != nil {
+			t.Fatalf("save claimed slide: %v", err)
+		}
+		f.clock.Advance(time.Minute)
+	}
+	if _, err := f.s.GetNextQueuedSlide(ctx); !errors.Is(err, domain.ErrNoQueuedSlides) {
+		t.Errorf("next queued slide after claiming all: err = %v, want %v", err, domain.ErrNoQueuedSlides)
+	}
+
+	// The slides were claimed a minute apart, starting at claimedAt.
+	claimedAt := start.Add(2 * time.Minute)
+	processing, err := f.s.GetProcessingSlidesStartedBefore(ctx, claimedAt.Add(time.Minute+time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uuid.UUID{first.ID, older.ID}; !slices.Equal(slideIDsInOrder(processing), want) {
+		t.Errorf("processing slides started before = %v, want %v", slideIDsInOrder(processing), want)
+	}
+
+	expired, err := f.s.GetSlidesWithLeaseExpiredBefore(ctx, claimedAt.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 0 {
+		t.Errorf("slides with lease expired on time = %v, want none", slideIDs(expired))
+	}
+	expired, err = f.s.GetSlidesWithLeaseExpiredBefore(ctx, claimedAt.Add(time.Hour+time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uuid.UUID{first.ID}; !slices.Equal(slideIDs(expired), want) {
+		t.Errorf("slides with lease expired before = %v, want %v", slideIDs(expired), want)
+	}
+}
+
+func testSoftDeleteSlide(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	c, block := f.saveCase(t)
+	slides := f.saveSlides(t, c, block, "S-1", "S-2")
+
+	deleted, err := f.service.DeleteSlide(slides[0], slides, "broken glass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveSlide(ctx, deleted); err != nil {
+		t.Fatalf("save deleted slide: %v", err)
+	}
+
+	if _, err := f.s.GetSlide(ctx, deleted.ID); !errors.Is(err, domain.ErrSlideNotFound) {
+		t.Errorf("get deleted slide: err = %v, want %v", err, domain.ErrSlideNotFound)
+	}
+	if _, err := f.s.GetSlideByBarcode(ctx, deleted.Barcode); !errors.Is(err, domain.ErrSlideNotFound) {
+		t.Errorf("get deleted slide by barcode: err = %v, want %v", err, domain.ErrSlideNotFound)
+	}
+	caseSlides, err := f.s.GetSlidesByCaseID(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := slideIDs(caseSlides), []uuid.UUID{slides[1].ID}; !slices.Equal(got, want) {
+		t.Errorf("case slides = %v, want %v", got, want)
+	}
+
+	got, err := f.s.GetDeletedSlide(ctx, deleted.ID)
+	if err != nil {
+		t.Fatalf("get deleted slide: %v", err)
+	}
+	if !got.DeletedAt.Equal(start) {
+		t.Errorf("deleted at %v, want %v", got.DeletedAt, start)
+	}
+	if _, err := f.s.GetDeletedSlide(ctx, slides[1].ID); !errors.Is(err, domain.ErrSlideNotFound) {
+		t.Errorf("get live slide as deleted: err = %v, want %v", err, domain.ErrSlideNotFound)
+	}
+
+	expired, err := f.s.GetSlidesDeletedBefore(ctx, start.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := slideIDs(expired), []uuid.UUID{deleted.ID}; !slices.Equal(got, want) {
+		t.Errorf("slides deleted before = %v, want %v", got, want)
+	}
+	if expired, err := f.s.GetSlidesDeletedBefore(ctx, start); err != nil || len(expired) != 0 {
+		t.Errorf("slides deleted before the deletion = %v, %v, want none", slideIDs(expired), err)
+	}
+
+	if err := f.s.DeleteSlide(ctx, got); err != nil {
+		t.Fatalf("purge slide: %v", err)
+	}
+	if _, err := f.s.GetDeletedSlide(ctx, deleted.ID); !errors.Is(err, domain.ErrSlideNotFound) {
+		t.Errorf("get purged slide: err = %v, want %v", err, domain.ErrSlideNotFound)
+	}
+}
+
+func testSoftDeleteCase(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	c, block := f.saveCase(t)
+	slides := f.saveSlides(t, c, block, "S-1")
+	c = f.getCase(t, c.ID)
+
+	if err := c.Delete(f.env, "duplicate accession"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveCase(ctx, c); err != nil {
+		t.Fatalf("save deleted case: %v", err)
+	}
+
+	if _, err := f.s.GetCase(ctx, c.ID); !errors.Is(err, domain.ErrCaseNotFound) {
+		t.Errorf("get deleted case: err = %v, want %v", err, domain.ErrCaseNotFound)
+	}
+	if _, err := f.s.GetSlide(ctx, slides[0].ID); !errors.Is(err, domain.ErrSlideNotFound) {
+		t.Errorf("get slide of deleted case: err = %v, want %v", err, domain.ErrSlideNotFound)
+	}
+	if _, err := f.s.GetSlideByBarcode(ctx, "S-1"); !errors.Is(err, domain.ErrSlideNotFound) {
+		t.Errorf("get slide of deleted case by barcode: err = %v, want %v", err, domain.ErrSlideNotFound)
+	}
+	if taken, err := f.s.GetSlidesByBarcodes(ctx, []string{"S-1"}); err != nil || len(taken) != 0 {
+		t.Errorf("slides of deleted case by barcodes = %v, %v, want none", slideIDs(taken), err)
+	}
+	if caseSlides, err := f.s.GetSlidesByCaseID(ctx, c.ID); err != nil || len(caseSlides) != 0 {
+		t.Errorf("slides of deleted case = %v, %v, want none", slideIDs(caseSlides), err)
+	}
+
+	deleted, err := f.s.GetDeletedCase(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("get deleted case: %v", err)
+	}
+	if !deleted.DeletedAt.Equal(start) {
+		t.Errorf("deleted at %v, want %v", deleted.DeletedAt, start)
+	}
+	ids, err := f.s.GetCaseIDsDeletedBefore(ctx, start.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ids, []uuid.UUID{c.ID}) {
+		t.Errorf("cases deleted before = %v, want %v", ids, []uuid.UUID{c.ID})
+	}
+
+	f.clock.Advance(time.Hour)
+	if err := deleted.Restore(f.env, domain.DeletionPolicy{RestoreGrace: 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveCase(ctx, deleted); err != nil {
+		t.Fatalf("save restored case: %v", err)
+	}
+	f.getCase(t, c.ID)
+	f.getSlide(t, slides[0].ID)
+
+	if _, err := f.s.GetDeletedCase(ctx, c.ID); !errors.Is(err, domain.ErrCaseNotFound) {
+		t.Errorf("get restored case as deleted: err = %v, want %v", err, domain.ErrCaseNotFound)
+	}
+}
+
+// testDeleteMergeTarget checks that a case others were merged into is not
+// removed, so their reference to it never changes behind their version.
+func testDeleteMergeTarget(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	source, _ := f.saveCase(t)
+	target, _ := f.saveCase(t)
+
+	if _, err := f.service.MergeCases(&source, &target, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveCase(ctx, source); err != nil {
+		t.Fatalf("save merged case: %v", err)
+	}
+	if err := f.s.SaveCase(ctx, target); err != nil {
+		t.Fatalf("save target case: %v", err)
+	}
+
+	target = f.getCase(t, target.ID)
+	if err := target.Delete(f.env, "duplicate accession"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.s.SaveCase(ctx, target); err != nil {
+		t.Fatalf("save deleted case: %v", err)
+	}
+	target, err := f.s.GetDeletedCase(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("get deleted case: %v", err)
+	}
+
+	err = f.s.WithTx(ctx, func(ctx context.Context) error {
+		return f.s.DeleteCase(ctx, target)
+	})
+	if !errors.Is(err, domain.ErrCaseHasMergedCases) {
+		t.Errorf("delete merge target: err = %v, want %v", err, domain.ErrCaseHasMergedCases)
+	}
+
+	merged := f.getCase(t, source.ID)
+	if merged.MergedInto != target.ID {
+		t.Errorf("merged into %v, want %v", merged.MergedInto, target.ID)
+	}
+	if _, err := f.s.GetDeletedCase(ctx, target.ID); err != nil {
+		t.Errorf("get deleted case after failed delete: %v", err)
+	}
+}
+
+func testOutbox(t *testing.T, f *fixture) {
+	ctx := context.Background()
+
+	var events []domain.Event
+	for range 3 {
+		c, err := domain.CreateCase(f.env, domain.CasePriorityRoutine)
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, c.PullEvents()...)
+		f.clock.Advance(time.Minute)
+	}
+	if err := f.s.AddEvent(ctx, events); err != nil {
+		t.Fatalf("add events: %v", err)
+	}
+
+	unpublished, err := f.s.FetchUnpublishedEvents(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, "unpublished", unpublished, events)
+
+	limited, err := f.s.FetchUnpublishedEvents(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, "unpublished with limit", limited, events[:2])
+
+	if err := f.s.MarkEventPublished(ctx, events[:2]); err != nil {
+		t.Fatalf("mark published: %v", err)
+	}
+	unpublished, err = f.s.FetchUnpublishedEvents(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, "unpublished after publishing", unpublished, events[2:])
+
+	// Only published events older than the oldest unpublished one are
+	// archived, however late the cutoff.
+	archived, err := f.s.ArchiveEvents(ctx, start.Add(time.Hour), 1)
+	if err != nil {
+		t.Fatalf("archive events: %v", err)
+	}
+	if archived != 1 {
+		t.Errorf("archived %d events with limit 1, want 1", archived)
+	}
+	archived, err = f.s.ArchiveEvents(ctx, start.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("archive events: %v", err)
+	}
+	if archived != 1 {
+		t.Errorf("archived %d more events, want 1", archived)
+	}
+
+	all, err := f.s.FetchEvents(ctx, time.Time{}, uuid.Nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, "all events", all, events)
+
+	after, err := f.s.FetchEvents(ctx, events[0].CreatedAt(), events[0].EventID(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, "events after the first", after, events[1:])
+
+	pruned, err := f.s.PruneArchivedEvents(ctx, events[1].CreatedAt())
+	if err != nil {
+		t.Fatalf("prune archive: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned %d events, want 1", pruned)
+	}
+	all, err = f.s.FetchEvents(ctx, time.Time{}, uuid.Nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, "events after pruning", all, events[1:])
+}
+
+func testTxRollback(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	c, block := f.saveCase(t)
+
+	errAbort := errors.New("abort")
+	var created domain.Case
+	err := f.s.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = domain.CreateCase(f.env, domain.CasePriorityRoutine); err != nil {
+			return err
+		}
+		if err := f.s.SaveCase(ctx, created); err != nil {
+			return err
+		}
+		if err := f.s.AddEvent(ctx, created.PullEvents()); err != nil {
+			return err
+		}
+		if _, err := f.s.GetCase(ctx, created.ID); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithTx: err = %v, want %v", err, errAbort)
+	}
+
+	if _, err := f.s.GetCase(ctx, created.ID); !errors.Is(err, domain.ErrCaseNotFound) {
+		t.Errorf("get rolled back case: err = %v, want %v", err, domain.ErrCaseNotFound)
+	}
+	if events, err := f.s.FetchUnpublishedEvents(ctx, 10); err != nil || len(events) != 0 {
+		t.Errorf("unpublished events after rollback = %d, %v, want none", len(events), err)
+	}
+
+	slide, err := f.service.CreateSlide(c, nil, domain.SlideSpec{
+		Stain:   domain.Stain{Type: domain.StainTypeHE},
+		BlockID: block.ID,
+		Level:   1,
+		Barcode: "S-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.s.WithTx(ctx, func(ctx context.Context) error {
+		return f.s.SaveSlide(ctx, slide)
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if _, err := f.s.GetSlideByBarcode(ctx, "S-1"); err != nil {
+		t.Errorf("get committed slide: %v", err)
+	}
+}
+
+func testNestedTx(t *testing.T, f *fixture) {
+	ctx := context.Background()
+	c, _ := f.saveCase(t)
+
+	if _, ok := f.s.ActiveTx(ctx); ok {
+		t.Error("active tx outside a transaction")
+	}
+
+	// A read joins a writing transaction as long as it asks for no stronger
+	// isolation than the transaction has.
+	err := f.s.WithTx(ctx, func(ctx context.Context) error {
+		return f.s.WithTx(ctx, func(ctx context.Context) error {
+			opts, ok := f.s.ActiveTx(ctx)
+			if want := application.NewTxOptions(application.WithIsolation(application.IsolationRepeatableRead)); !ok || opts != want {
+				t.Errorf("active tx = %+v, %t, want %+v, true", opts, ok, want)
+			}
+			_, err := f.s.GetCase(ctx, c.ID)
+			return err
+		}, application.ReadOnly(), application.WithIsolation(application.IsolationRepeatableRead))
+	}, application.WithIsolation(application.IsolationRepeatableRead))
+	if err != nil {
+		t.Errorf("read-only repeatable read inside repeatable read tx: %v", err)
+	}
+
+	err = f.s.WithTx(ctx, func(ctx context.Context) error {
+		return f.s.WithTx(ctx, func(ctx context.Context) error {
+			return nil
+		}, application.ReadOnly(), application.WithIsolation(application.IsolationRepeatableRead))
+	})
+	if !errors.Is(err, application.ErrTxOptionsConflict) {
+		t.Errorf("read-only repeatable read inside default tx: err = %v, want %v", err, application.ErrTxOptionsConflict)
+	}
+
+	err = f.s.WithTx(ctx, func(ctx context.Context) error {
+		return f.s.WithTx(ctx, func(ctx context.Context) error {
+			return nil
+		}, application.WithIsolation(application.IsolationSerializable))
+	}, application.WithIsolation(application.IsolationRepeatableRead))
+	if !errors.Is(err, application.ErrTxOptionsConflict) {
+		t.Errorf("serializable inside repeatable read: err = %v, want %v", err, application.ErrTxOptionsConflict)
+	}
+
+	err = f.s.WithTx(ctx, func(ctx context.Context) error {
+		return f.s.WithTx(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	}, application.ReadOnly())
+	if !errors.Is(err, application.ErrTxOptionsConflict) {
+		t.Errorf("write inside read-only tx: err = %v, want %v", err, application.ErrTxOptionsConflict)
+	}
+}
+
+// assertEvents compares the events by ID, type and creation time, which are
+// kept as they are by every adapter.
+func assertEvents(t *testing.T, name string, got, want []domain.Event) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("%s: got %d events, want %d", name, len(got), len(want))
+		return
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.EventID() != w.EventID() || g.EventType() != w.EventType() || !g.CreatedAt().Equal(w.CreatedAt()) {
+			t.Errorf("%s[%d] = %s %s at %v, want %s %s at %v", name, i,
+				g.Name(), g.EventID(), g.CreatedAt(), w.Name(), w.EventID(), w.CreatedAt())
+		}
+	}
+}
+
+// slideIDsInOrder returns the slide IDs in the order the storage returned
+// them, for queries that promise one.
+func slideIDsInOrder(slides []domain.Slide) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(slides))
+	for _, slide := range slides {
+		ids = append(ids, slide.ID)
+	}
+	return ids
+}
+
+func slideIDs(slides []domain.Slide) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(slides))
+	for _, slide := range slides {
+		ids = append(ids, slide.ID)
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int {
+		return slices.Compare(a[:], b[:])
+	})
+	return ids
+}
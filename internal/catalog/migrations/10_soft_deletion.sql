@@ -0,0 +1,27 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE slides ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE cases ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX slides_deleted_at_idx ON slides (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX cases_deleted_at_idx ON cases (deleted_at) WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE case_projections ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE slide_turnarounds
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN case_deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE pathologist_worklist ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE pathologist_worklist DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE slide_turnarounds
+    DROP COLUMN IF EXISTS case_deleted_at,
+    DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE case_projections DROP COLUMN IF EXISTS deleted_at;
+
+DROP INDEX IF EXISTS cases_deleted_at_idx;
+DROP INDEX IF EXISTS slides_deleted_at_idx;
+
+ALTER TABLE cases DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE slides DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE INDEX events_unpublished_idx ON events (created_at) WHERE NOT published;
+
+CREATE TABLE events_archive (
+    id UUID PRIMARY KEY,
+    type SMALLINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    payload JSONB NOT NULL,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX events_archive_created_at_idx ON events_archive (created_at);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS events_archive;
+
+DROP INDEX IF EXISTS events_unpublished_idx;
@@ -0,0 +1,45 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- Slides that would break the constraints added next are moved aside instead
+-- of blocking the rollout: slides without an existing case or block, and
+-- slides from before stains and levels were recorded, which still carry
+-- stain_type 0 and level 0. They keep all their columns in slides_quarantine
+-- with the reason, so they can be fixed by hand and moved back.
+CREATE TABLE slides_quarantine (LIKE slides INCLUDING DEFAULTS);
+
+ALTER TABLE slides_quarantine
+    ADD COLUMN reason TEXT NOT NULL,
+    ADD COLUMN quarantined_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+WITH moved AS (
+    DELETE FROM slides s
+    WHERE s.case_id IS NULL
+       OR s.block_id IS NULL
+       OR NOT EXISTS (SELECT 1 FROM cases c WHERE c.id = s.case_id)
+       OR s.stain_type NOT BETWEEN 1 AND 3
+       OR s.level < 1
+    RETURNING s.*
+)
+INSERT INTO slides_quarantine
+SELECT moved.*,
+    CASE
+        WHEN moved.case_id IS NULL
+          OR moved.block_id IS NULL
+          OR NOT EXISTS (SELECT 1 FROM cases c WHERE c.id = moved.case_id)
+            THEN 'no existing case or block'
+        WHEN moved.stain_type NOT BETWEEN 1 AND 3 THEN 'unknown stain type'
+        ELSE 'level below 1'
+    END,
+    NOW()
+FROM moved;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE slides_quarantine
+    DROP COLUMN reason,
+    DROP COLUMN quarantined_at;
+
+INSERT INTO slides SELECT * FROM slides_quarantine;
+
+DROP TABLE slides_quarantine;
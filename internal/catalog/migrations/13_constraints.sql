@@ -0,0 +1,79 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+
+-- Rollout: this migration changes no rows. Slides that would break the new
+-- constraints are moved to slides_quarantine by the migration before it. If
+-- any were written since, it stops with the number of offending rows and
+-- leaves the schema as it was.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    orphaned BIGINT;
+    unknown_stain BIGINT;
+    no_level BIGINT;
+BEGIN
+    SELECT count(*) INTO orphaned
+    FROM slides s
+    WHERE s.case_id IS NULL
+       OR s.block_id IS NULL
+       OR NOT EXISTS (SELECT 1 FROM cases c WHERE c.id = s.case_id);
+
+    SELECT count(*) INTO unknown_stain FROM slides WHERE stain_type NOT BETWEEN 1 AND 3;
+    SELECT count(*) INTO no_level FROM slides WHERE level < 1;
+
+    IF orphaned + unknown_stain + no_level > 0 THEN
+        RAISE EXCEPTION 'slides break the new constraints: % without an existing case or block, % with an unknown stain type, % with a level below 1',
+            orphaned, unknown_stain, no_level
+            USING HINT = 'Fix or quarantine these slides, then apply the migration again.';
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE slides
+    ALTER COLUMN case_id SET NOT NULL,
+    ALTER COLUMN block_id SET NOT NULL,
+    ALTER COLUMN stain_type DROP DEFAULT,
+    ALTER COLUMN level DROP DEFAULT,
+    ADD CONSTRAINT slides_case_id_fkey FOREIGN KEY (case_id) REFERENCES cases (id),
+    ADD CONSTRAINT slides_preparation_status_check CHECK (preparation_status BETWEEN 1 AND 4),
+    ADD CONSTRAINT slides_stain_type_check CHECK (stain_type BETWEEN 1 AND 3),
+    ADD CONSTRAINT slides_level_check CHECK (level >= 1);
+
+CREATE INDEX slides_case_id_idx ON slides (case_id);
+CREATE INDEX slides_block_id_idx ON slides (block_id);
+
+ALTER TABLE cases
+    ADD CONSTRAINT cases_merged_into_fkey FOREIGN KEY (merged_into) REFERENCES cases (id),
+    ADD CONSTRAINT cases_status_check CHECK (status BETWEEN 1 AND 5),
+    ADD CONSTRAINT cases_priority_check CHECK (priority BETWEEN 1 AND 3);
+
+ALTER TABLE case_assignments
+    ADD CONSTRAINT case_assignments_pathologist_check CHECK (pathologist <> '');
+
+ALTER TABLE events
+    ADD CONSTRAINT events_type_check CHECK (type > 0);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_type_check;
+
+ALTER TABLE case_assignments DROP CONSTRAINT IF EXISTS case_assignments_pathologist_check;
+
+ALTER TABLE cases
+    DROP CONSTRAINT IF EXISTS cases_priority_check,
+    DROP CONSTRAINT IF EXISTS cases_status_check,
+    DROP CONSTRAINT IF EXISTS cases_merged_into_fkey;
+
+DROP INDEX IF EXISTS slides_block_id_idx;
+DROP INDEX IF EXISTS slides_case_id_idx;
+
+ALTER TABLE slides
+    DROP CONSTRAINT IF EXISTS slides_level_check,
+    DROP CONSTRAINT IF EXISTS slides_stain_type_check,
+    DROP CONSTRAINT IF EXISTS slides_preparation_status_check,
+    DROP CONSTRAINT IF EXISTS slides_case_id_fkey,
+    ALTER COLUMN level SET DEFAULT 0,
+    ALTER COLUMN stain_type SET DEFAULT 0,
+    ALTER COLUMN block_id DROP NOT NULL,
+    ALTER COLUMN case_id DROP NOT NULL;
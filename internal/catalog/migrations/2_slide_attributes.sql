@@ -0,0 +1,27 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE slides
+    ADD COLUMN stain_type SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN stain_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN block TEXT NOT NULL DEFAULT '',
+    ADD COLUMN level INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN barcode TEXT,
+    ADD COLUMN label TEXT NOT NULL DEFAULT '';
+
+UPDATE slides SET barcode = id::TEXT WHERE barcode IS NULL;
+
+ALTER TABLE slides ALTER COLUMN barcode SET NOT NULL;
+
+CREATE UNIQUE INDEX slides_barcode_key ON slides (barcode);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP INDEX IF EXISTS slides_barcode_key;
+
+ALTER TABLE slides
+    DROP COLUMN IF EXISTS stain_type,
+    DROP COLUMN IF EXISTS stain_name,
+    DROP COLUMN IF EXISTS block,
+    DROP COLUMN IF EXISTS level,
+    DROP COLUMN IF EXISTS barcode,
+    DROP COLUMN IF EXISTS label;
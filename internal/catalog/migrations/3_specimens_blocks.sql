@@ -0,0 +1,55 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE specimens (
+    id UUID PRIMARY KEY,
+    case_id UUID NOT NULL REFERENCES cases (id),
+    label TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (case_id, label)
+);
+
+CREATE TABLE blocks (
+    id UUID PRIMARY KEY,
+    specimen_id UUID NOT NULL REFERENCES specimens (id),
+    label TEXT NOT NULL,
+    UNIQUE (specimen_id, label)
+);
+
+ALTER TABLE slides ADD COLUMN block_id UUID REFERENCES blocks (id);
+
+-- Existing slides are attached to a single specimen per case with one block
+-- per previously recorded block identifier.
+INSERT INTO specimens (id, case_id, label, description)
+SELECT uuid_generate_v4(), s.case_id, 'A', ''
+FROM slides s
+JOIN cases c ON c.id = s.case_id
+GROUP BY s.case_id;
+
+INSERT INTO blocks (id, specimen_id, label)
+SELECT uuid_generate_v4(), sp.id, COALESCE(NULLIF(s.block, ''), 'A1')
+FROM specimens sp
+JOIN slides s ON s.case_id = sp.case_id
+GROUP BY sp.id, COALESCE(NULLIF(s.block, ''), 'A1');
+
+UPDATE slides s
+SET block_id = b.id
+FROM blocks b
+JOIN specimens sp ON sp.id = b.specimen_id
+WHERE sp.case_id = s.case_id
+  AND b.label = COALESCE(NULLIF(s.block, ''), 'A1');
+
+ALTER TABLE slides DROP COLUMN block;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE slides ADD COLUMN block TEXT NOT NULL DEFAULT '';
+
+UPDATE slides s
+SET block = b.label
+FROM blocks b
+WHERE b.id = s.block_id;
+
+ALTER TABLE slides DROP COLUMN IF EXISTS block_id;
+
+DROP TABLE IF EXISTS blocks;
+DROP TABLE IF EXISTS specimens;
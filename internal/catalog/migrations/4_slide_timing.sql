@@ -0,0 +1,37 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE cases ADD COLUMN priority SMALLINT NOT NULL DEFAULT 1;
+
+ALTER TABLE slides
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN started_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN finished_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN failed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN failure_reason TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE slide_turnarounds (
+    slide_id UUID PRIMARY KEY,
+    case_id UUID NOT NULL,
+    stain_type SMALLINT NOT NULL,
+    stain_name TEXT NOT NULL,
+    case_priority SMALLINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX slide_turnarounds_finished_at_idx ON slide_turnarounds (finished_at);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS slide_turnarounds;
+
+ALTER TABLE slides
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS started_at,
+    DROP COLUMN IF EXISTS finished_at,
+    DROP COLUMN IF EXISTS failed_at,
+    DROP COLUMN IF EXISTS failure_reason;
+
+ALTER TABLE cases DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,70 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE case_projections RENAME COLUMN status TO preparation_status;
+
+ALTER TABLE case_projections
+    ADD COLUMN status SMALLINT NOT NULL DEFAULT 1,
+    ADD COLUMN priority SMALLINT NOT NULL DEFAULT 1,
+    ADD COLUMN slides_not_started INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN slides_processing INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN slides_done INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN slides_error INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+
+CREATE TABLE case_projection_slides (
+    slide_id UUID PRIMARY KEY,
+    case_id UUID NOT NULL,
+    status SMALLINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX case_projection_slides_case_id_idx ON case_projection_slides (case_id);
+
+INSERT INTO case_projections (id, preparation_status, status, priority)
+SELECT id, 1, status, priority
+FROM cases
+ON CONFLICT (id) DO UPDATE
+SET status = EXCLUDED.status,
+    priority = EXCLUDED.priority;
+
+INSERT INTO case_projection_slides (slide_id, case_id, status, updated_at)
+SELECT id, case_id, preparation_status, COALESCE(created_at, now())
+FROM slides
+WHERE case_id IS NOT NULL;
+
+UPDATE case_projections cp
+SET slides_not_started = c.not_started,
+    slides_processing = c.processing,
+    slides_done = c.done,
+    slides_error = c.error
+FROM (
+    SELECT case_id,
+        COUNT(*) FILTER (WHERE status = 1) AS not_started,
+        COUNT(*) FILTER (WHERE status = 2) AS processing,
+        COUNT(*) FILTER (WHERE status = 3) AS done,
+        COUNT(*) FILTER (WHERE status = 4) AS error
+    FROM case_projection_slides
+    GROUP BY case_id
+) c
+WHERE cp.id = c.case_id;
+
+CREATE INDEX case_projections_created_at_idx ON case_projections (created_at, id);
+CREATE INDEX case_projections_updated_at_idx ON case_projections (updated_at, id);
+CREATE INDEX case_projections_priority_idx ON case_projections (priority, id);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS case_projection_slides;
+
+ALTER TABLE case_projections
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS priority,
+    DROP COLUMN IF EXISTS slides_not_started,
+    DROP COLUMN IF EXISTS slides_processing,
+    DROP COLUMN IF EXISTS slides_done,
+    DROP COLUMN IF EXISTS slides_error,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS updated_at;
+
+ALTER TABLE case_projections RENAME COLUMN preparation_status TO status;
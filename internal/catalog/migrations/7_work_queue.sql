@@ -0,0 +1,17 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE slides
+    ADD COLUMN assigned_to TEXT NOT NULL DEFAULT '',
+    ADD COLUMN lease_expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX slides_queue_idx ON slides (created_at) WHERE preparation_status = 1;
+CREATE INDEX slides_lease_expires_at_idx ON slides (lease_expires_at) WHERE lease_expires_at IS NOT NULL;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP INDEX IF EXISTS slides_lease_expires_at_idx;
+DROP INDEX IF EXISTS slides_queue_idx;
+
+ALTER TABLE slides
+    DROP COLUMN IF EXISTS assigned_to,
+    DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE cases ADD COLUMN pathologist TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE case_assignments (
+    id UUID PRIMARY KEY,
+    case_id UUID NOT NULL REFERENCES cases (id),
+    pathologist TEXT NOT NULL,
+    assigned_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX case_assignments_case_id_idx ON case_assignments (case_id);
+
+CREATE TABLE pathologist_worklist (
+    case_id UUID PRIMARY KEY,
+    pathologist TEXT NOT NULL,
+    status SMALLINT NOT NULL,
+    priority SMALLINT NOT NULL,
+    preparation_status SMALLINT NOT NULL,
+    assigned_at TIMESTAMP WITH TIME ZONE,
+    ready_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX pathologist_worklist_pathologist_idx ON pathologist_worklist (pathologist, preparation_status);
+
+INSERT INTO pathologist_worklist (case_id, pathologist, status, priority, preparation_status)
+SELECT id, '', status, priority, preparation_status
+FROM case_projections;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS pathologist_worklist;
+DROP TABLE IF EXISTS case_assignments;
+
+ALTER TABLE cases DROP COLUMN IF EXISTS pathologist;
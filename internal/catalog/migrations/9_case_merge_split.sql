@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE cases ADD COLUMN merged_into UUID;
+
+ALTER TABLE case_projections ADD COLUMN merged_into UUID;
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE case_projections DROP COLUMN IF EXISTS merged_into;
+
+ALTER TABLE cases DROP COLUMN IF EXISTS merged_into;
@@ -0,0 +1,15 @@
+package migrations
+
+import "embed"
+
+// FS holds the goose migrations, so the binary does not depend on the
+// working directory.
+//
+//go:embed *.sql
+var FS embed.FS
+
+// SQLiteFS holds the migrations of the SQLite storage under "sqlite". The
+// SQLite schema covers the write model only, projections need Postgres.
+//
+//go:embed sqlite/*.sql
+var SQLiteFS embed.FS
@@ -0,0 +1,105 @@
+-- +goose Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE cases (
+    id TEXT PRIMARY KEY,
+    version INTEGER NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 1,
+    status INTEGER NOT NULL DEFAULT 1,
+    pathologist TEXT NOT NULL DEFAULT '',
+    merged_into TEXT,
+    deleted_at TIMESTAMP,
+    CONSTRAINT cases_merged_into_fkey FOREIGN KEY (merged_into) REFERENCES cases (id),
+    CONSTRAINT cases_status_check CHECK (status BETWEEN 1 AND 5),
+    CONSTRAINT cases_priority_check CHECK (priority BETWEEN 1 AND 3)
+);
+
+CREATE INDEX cases_deleted_at_idx ON cases (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE specimens (
+    id TEXT PRIMARY KEY,
+    case_id TEXT NOT NULL REFERENCES cases (id),
+    label TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (case_id, label)
+);
+
+CREATE TABLE blocks (
+    id TEXT PRIMARY KEY,
+    specimen_id TEXT NOT NULL REFERENCES specimens (id),
+    label TEXT NOT NULL,
+    UNIQUE (specimen_id, label)
+);
+
+CREATE TABLE case_assignments (
+    id TEXT PRIMARY KEY,
+    case_id TEXT NOT NULL REFERENCES cases (id),
+    pathologist TEXT NOT NULL,
+    assigned_at TIMESTAMP NOT NULL,
+    CONSTRAINT case_assignments_pathologist_check CHECK (pathologist <> '')
+);
+
+CREATE INDEX case_assignments_case_id_idx ON case_assignments (case_id);
+
+CREATE TABLE slides (
+    id TEXT PRIMARY KEY,
+    version INTEGER NOT NULL,
+    preparation_status INTEGER NOT NULL,
+    case_id TEXT NOT NULL,
+    stain_type INTEGER NOT NULL,
+    stain_name TEXT NOT NULL DEFAULT '',
+    block_id TEXT NOT NULL REFERENCES blocks (id),
+    level INTEGER NOT NULL,
+    barcode TEXT NOT NULL,
+    label TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    failed_at TIMESTAMP,
+    failure_reason TEXT NOT NULL DEFAULT '',
+    assigned_to TEXT NOT NULL DEFAULT '',
+    lease_expires_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT slides_case_id_fkey FOREIGN KEY (case_id) REFERENCES cases (id),
+    CONSTRAINT slides_preparation_status_check CHECK (preparation_status BETWEEN 1 AND 4),
+    CONSTRAINT slides_stain_type_check CHECK (stain_type BETWEEN 1 AND 3),
+    CONSTRAINT slides_level_check CHECK (level >= 1)
+);
+
+CREATE UNIQUE INDEX slides_barcode_key ON slides (barcode);
+CREATE INDEX slides_case_id_idx ON slides (case_id);
+CREATE INDEX slides_block_id_idx ON slides (block_id);
+CREATE INDEX slides_queue_idx ON slides (created_at) WHERE preparation_status = 1;
+CREATE INDEX slides_lease_expires_at_idx ON slides (lease_expires_at) WHERE lease_expires_at IS NOT NULL;
+CREATE INDEX slides_deleted_at_idx ON slides (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE events (
+    id TEXT PRIMARY KEY,
+    type INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    published BOOLEAN NOT NULL,
+    payload BLOB NOT NULL,
+    CONSTRAINT events_type_check CHECK (type > 0)
+);
+
+CREATE INDEX events_unpublished_idx ON events (created_at) WHERE NOT published;
+CREATE INDEX events_created_at_id_idx ON events (created_at, id);
+
+CREATE TABLE events_archive (
+    id TEXT PRIMARY KEY,
+    type INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    payload BLOB NOT NULL,
+    archived_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX events_archive_created_at_idx ON events_archive (created_at);
+
+-- +goose Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE IF EXISTS events_archive;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS slides;
+DROP TABLE IF EXISTS case_assignments;
+DROP TABLE IF EXISTS blocks;
+DROP TABLE IF EXISTS specimens;
+DROP TABLE IF EXISTS cases;
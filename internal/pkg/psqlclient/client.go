@@ -3,33 +3,16 @@ package psqlclient
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 )
 
-func MustNew(cfg Config) *sqlx.DB {
-	dsn := fmt.Sprintf(
-		"postgresql://%s:%s@%s:%s/%s",
-		cfg.Username,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Database,
-	)
-
-	db := sqlx.MustOpen("pgx", dsn)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := db.PingContext(ctx); err != nil {
-		panic("ping db")
-	}
-
-	return db
-}
+const maxConnectBackoff = 30 * time.Second
 
 type Config struct {
 	Username string
@@ -37,4 +20,99 @@ type Config struct {
 	Host     string
 	Port     string
 	Database string
+
+	// SSLMode and SSLRootCert are passed to the driver as is. An empty
+	// SSLMode leaves the driver default, which is prefer.
+	SSLMode         string
+	SSLRootCert     string
+	ApplicationName string
+
+	// Zero values keep the database/sql defaults.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// StatementTimeout is set for every session. Zero means no timeout.
+	StatementTimeout time.Duration
+
+	// The database is pinged up to ConnectRetries+1 times, each attempt
+	// limited by ConnectTimeout. The wait between attempts starts at
+	// ConnectBackoff and doubles up to 30s.
+	ConnectTimeout time.Duration
+	ConnectRetries int
+	ConnectBackoff time.Duration
+}
+
+// New opens a pool and waits until the database answers, retrying while it
+// is still starting up.
+func New(ctx context.Context, cfg Config) (*sqlx.DB, error) {
+	db, err := sqlx.Open("pgx", dsn(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	if err := ping(ctx, db, cfg); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping db %s: %w", net.JoinHostPort(cfg.Host, cfg.Port), err)
+	}
+
+	return db, nil
+}
+
+func ping(ctx context.Context, db *sqlx.DB, cfg Config) error {
+	backoff := cfg.ConnectBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := pingOnce(ctx, db, cfg.ConnectTimeout)
+		if err == nil || attempt >= cfg.ConnectRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
+
+func pingOnce(ctx context.Context, db *sqlx.DB, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return db.PingContext(ctx)
+}
+
+func dsn(cfg Config) string {
+	params := url.Values{}
+	if cfg.SSLMode != "" {
+		params.Set("sslmode", cfg.SSLMode)
+	}
+	if cfg.SSLRootCert != "" {
+		params.Set("sslrootcert", cfg.SSLRootCert)
+	}
+	if cfg.ApplicationName != "" {
+		params.Set("application_name", cfg.ApplicationName)
+	}
+	if cfg.StatementTimeout > 0 {
+		params.Set("statement_timeout", strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10))
+	}
+
+	u := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(cfg.Username, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, cfg.Port),
+		Path:     "/" + cfg.Database,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
 }
@@ -0,0 +1,60 @@
+package psqlclient
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type primaryKey struct{}
+
+// WithPrimary makes reads under ctx use the primary even when a replica is
+// configured, for callers that must see their own writes.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// Router sends query-side reads to a read replica and everything else to the
+// primary. Without a replica every read goes to the primary as well.
+//
+// With readYourWrites set, reads stay on the primary for that long after the
+// last write, which covers the replication lag for clients that read right
+// after writing. Writes are reported with Wrote, and every write made through
+// Writer reports itself.
+type Router struct {
+	primary        *sqlx.DB
+	replica        *sqlx.DB
+	readYourWrites time.Duration
+	lastWrite      atomic.Int64
+}
+
+func NewRouter(primary, replica *sqlx.DB, readYourWrites time.Duration) *Router {
+	return &Router{
+		primary:        primary,
+		replica:        replica,
+		readYourWrites: readYourWrites,
+	}
+}
+
+func (r *Router) Primary() *sqlx.DB {
+	return r.primary
+}
+
+func (r *Router) Reader(ctx context.Context) *sqlx.DB {
+	if r.replica == nil {
+		return r.primary
+	}
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return r.primary
+	}
+	if r.readYourWrites > 0 && time.Since(time.Unix(0, r.lastWrite.Load())) < r.readYourWrites {
+		return r.primary
+	}
+	return r.replica
+}
+
+func (r *Router) Wrote() {
+	r.lastWrite.Store(time.Now().UnixNano())
+}
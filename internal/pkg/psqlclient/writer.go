@@ -0,0 +1,76 @@
+package psqlclient
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Writer runs statements on the primary and reports every write to the
+// router, so writes made outside a transaction start the read-your-writes
+// window as well. Transactions begun with BeginTxx report their commit.
+type Writer struct {
+	db     *sqlx.DB
+	router *Router
+}
+
+// Writer returns the primary for writes. Reads through it stay on the
+// primary without being reported.
+func (r *Router) Writer() *Writer {
+	return &Writer{db: r.primary, router: r}
+}
+
+func (w *Writer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := w.db.ExecContext(ctx, query, args...)
+	if err == nil {
+		w.router.Wrote()
+	}
+	return result, err
+}
+
+func (w *Writer) NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error) {
+	result, err := w.db.NamedExecContext(ctx, query, arg)
+	if err == nil {
+		w.router.Wrote()
+	}
+	return result, err
+}
+
+func (w *Writer) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return w.db.GetContext(ctx, dest, query, args...)
+}
+
+func (w *Writer) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return w.db.SelectContext(ctx, dest, query, args...)
+}
+
+func (w *Writer) Rebind(query string) string {
+	return w.db.Rebind(query)
+}
+
+// BeginTxx starts a transaction whose commit is reported unless it is
+// read-only.
+func (w *Writer) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*WriterTx, error) {
+	tx, err := w.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &WriterTx{Tx: tx, router: w.router, readOnly: opts != nil && opts.ReadOnly}, nil
+}
+
+type WriterTx struct {
+	*sqlx.Tx
+	router   *Router
+	readOnly bool
+}
+
+func (tx *WriterTx) Commit() error {
+	if err := tx.Tx.Commit(); err != nil {
+		return err
+	}
+	if !tx.readOnly {
+		tx.router.Wrote()
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package psqlclient
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// The router does not depend on the driver, so SQLite stands in for both
+// the primary and the replica.
+func newTestRouter(t *testing.T) (*Router, *sqlx.DB) {
+	t.Helper()
+
+	open := func() *sqlx.DB {
+		db, err := sqlx.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { _ = db.Close() })
+		return db
+	}
+	primary := open()
+	return NewRouter(primary, open(), time.Minute), primary
+}
+
+func TestWriterReportsWrites(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		write     func(w *Writer) error
+		wantWrote bool
+	}{
+		{
+			name: "exec",
+			write: func(w *Writer) error {
+				_, err := w.ExecContext(ctx, `INSERT INTO t (v) VALUES (1)`)
+				return err
+			},
+			wantWrote: true,
+		},
+		{
+			name: "named exec",
+			write: func(w *Writer) error {
+				_, err := w.NamedExecContext(ctx, `INSERT INTO t (v) VALUES (:v)`, map[string]any{"v": 1})
+				return err
+			},
+			wantWrote: true,
+		},
+		{
+			name: "failed exec",
+			write: func(w *Writer) error {
+				_, _ = w.ExecContext(ctx, `INSERT INTO missing (v) VALUES (1)`)
+				return nil
+			},
+		},
+		{
+			name: "select",
+			write: func(w *Writer) error {
+				var n int
+				return w.GetContext(ctx, &n, `SELECT COUNT(*) FROM t`)
+			},
+		},
+		{
+			name: "committed tx",
+			write: func(w *Writer) error {
+				tx, err := w.BeginTxx(ctx, nil)
+				if err != nil {
+					return err
+				}
+				if _, err := tx.ExecContext(ctx, `INSERT INTO t (v) VALUES (1)`); err != nil {
+					return err
+				}
+				return tx.Commit()
+			},
+			wantWrote: true,
+		},
+		{
+			name: "read-only tx",
+			write: func(w *Writer) error {
+				tx, err := w.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+				if err != nil {
+					return err
+				}
+				return tx.Commit()
+			},
+		},
+		{
+			name: "rolled back tx",
+			write: func(w *Writer) error {
+				tx, err := w.BeginTxx(ctx, nil)
+				if err != nil {
+					return err
+				}
+				if _, err := tx.ExecContext(ctx, `INSERT INTO t (v) VALUES (1)`); err != nil {
+					return err
+				}
+				return tx.Rollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, primary := newTestRouter(t)
+			if _, err := primary.Exec(`CREATE TABLE t (v INTEGER)`); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.write(router.Writer()); err != nil {
+				t.Fatal(err)
+			}
+
+			if wrote := router.Reader(ctx) == primary; wrote != tt.wantWrote {
+				t.Errorf("reads go to the primary: %v, want %v", wrote, tt.wantWrote)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package sqliteclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// New opens the database file with foreign keys enforced. Transactions
+// start with BEGIN IMMEDIATE, so concurrent writers wait for each other
+// instead of failing when a read lock is upgraded.
+func New(ctx context.Context, cfg Config) (*sqlx.DB, error) {
+	dsn := fmt.Sprintf(
+		"file:%s?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate",
+		cfg.Path,
+	)
+
+	db, err := sqlx.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping db %s: %w", cfg.Path, err)
+	}
+
+	return db, nil
+}
+
+type Config struct {
+	Path string
+}